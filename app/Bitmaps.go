@@ -0,0 +1,143 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+
+	"github.com/emicklei/go-restful"
+
+	resimage "github.com/inkyblackness/res/image"
+)
+
+// imageOptions describe how a bitmap is rendered into an image file.
+type imageOptions struct {
+	paletteID   string
+	transparent bool
+	scale       int
+}
+
+// imageOptionsFromRequest reads the query parameters "palette", "transparent" and "scale".
+func imageOptionsFromRequest(request *restful.Request) (options imageOptions, err error) {
+	options.paletteID = "game"
+	options.scale = 1
+
+	if paletteParam := request.QueryParameter("palette"); paletteParam != "" {
+		options.paletteID = paletteParam
+	}
+	if transparentParam := request.QueryParameter("transparent"); transparentParam != "" {
+		options.transparent, err = strconv.ParseBool(transparentParam)
+		if err != nil {
+			return
+		}
+	}
+	if scaleParam := request.QueryParameter("scale"); scaleParam != "" {
+		options.scale, err = strconv.Atoi(scaleParam)
+		if (err == nil) && ((options.scale < 1) || (options.scale > maxImageScale)) {
+			err = fmt.Errorf("Scale must be between 1 and %d", maxImageScale)
+		}
+	}
+
+	return
+}
+
+// apply returns an image based on given one, with transparency and scaling applied.
+// The palette of the given image is not modified.
+func (options imageOptions) apply(img *image.Paletted) *image.Paletted {
+	result := img
+
+	if options.transparent && (len(img.Palette) > 0) {
+		palette := make(color.Palette, len(img.Palette))
+		copy(palette, img.Palette)
+		palette[0] = color.RGBA{R: 0, G: 0, B: 0, A: 0}
+		result = &image.Paletted{Pix: img.Pix, Stride: img.Stride, Rect: img.Rect, Palette: palette}
+	}
+	if options.scale > 1 {
+		result = scaledImage(result, options.scale)
+	}
+
+	return result
+}
+
+// scaledImage returns a nearest-neighbour upscaled copy of given image.
+func scaledImage(img *image.Paletted, scale int) *image.Paletted {
+	bounds := img.Bounds()
+	scaled := image.NewPaletted(image.Rect(0, 0, bounds.Dx()*scale, bounds.Dy()*scale), img.Palette)
+
+	for y := 0; y < scaled.Rect.Dy(); y++ {
+		for x := 0; x < scaled.Rect.Dx(); x++ {
+			scaled.Pix[y*scaled.Stride+x] = img.ColorIndexAt(bounds.Min.X+x/scale, bounds.Min.Y+y/scale)
+		}
+	}
+
+	return scaled
+}
+
+// palettedImage returns an image referencing the given palette, with the
+// pixel data copied from the bitmap.
+func palettedImage(bmp resimage.Bitmap, palette color.Palette) *image.Paletted {
+	width := int(bmp.ImageWidth())
+	height := int(bmp.ImageHeight())
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+
+	for row := 0; row < height; row++ {
+		copy(img.Pix[row*img.Stride:row*img.Stride+width], bmp.Row(row))
+	}
+
+	return img
+}
+
+// rawPixels returns the pixel data of the bitmap, row by row.
+func rawPixels(bmp resimage.Bitmap) []byte {
+	width := int(bmp.ImageWidth())
+	height := int(bmp.ImageHeight())
+	pixel := make([]byte, width*height)
+
+	for row := 0; row < height; row++ {
+		copy(pixel[row*width:(row+1)*width], bmp.Row(row))
+	}
+
+	return pixel
+}
+
+// validateIconSize returns an error if the given image is empty or exceeds the bounds of an object icon.
+func validateIconSize(img image.Image) error {
+	bounds := img.Bounds()
+
+	if bounds.Empty() || (bounds.Dx() > maxObjectIconSize) || (bounds.Dy() > maxObjectIconSize) {
+		return fmt.Errorf("Icon size must be between 1x1 and %dx%d, is %dx%d",
+			maxObjectIconSize, maxObjectIconSize, bounds.Dx(), bounds.Dy())
+	}
+
+	return nil
+}
+
+// quantizedImage maps the given image onto the palette, using the closest colour for each pixel.
+// Pixels that are mostly transparent are mapped to palette index 0.
+func quantizedImage(img image.Image, palette color.Palette) *image.Paletted {
+	bounds := img.Bounds()
+	result := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), palette)
+	// Index 0 is reserved for transparency and must not be picked for opaque pixels.
+	opaquePalette := palette[1:]
+	cache := map[color.Color]uint8{}
+
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			pixel := img.At(bounds.Min.X+x, bounds.Min.Y+y)
+			_, _, _, alpha := pixel.RGBA()
+			index := uint8(0)
+
+			if alpha >= 0x8000 {
+				var cached bool
+				if index, cached = cache[pixel]; !cached {
+					index = uint8(opaquePalette.Index(pixel) + 1)
+					cache[pixel] = index
+				}
+			}
+			result.Pix[y*result.Stride+x] = index
+		}
+	}
+
+	return result
+}
@@ -8,3 +8,53 @@ const (
 	// Title contains a combined string of name and version
 	Title = Name + " v." + Version
 )
+
+const (
+	// defaultTextureAnimationFrameDelay is the time, in 100ths of a second, each frame of an
+	// animated texture is shown unless requested otherwise. The actual rate of each animation
+	// group is stored with the level, which the texture resources do not refer to; this value
+	// only approximates it.
+	defaultTextureAnimationFrameDelay = 25
+	// maxTextureAnimationFrameDelay is the longest accepted frame delay, in 100ths of a second.
+	maxTextureAnimationFrameDelay = 1000
+)
+
+const (
+	// defaultDuplicateThreshold is the number of bits two perceptual hashes may differ
+	// in for their images to be considered alike.
+	defaultDuplicateThreshold = 6
+	// maxDuplicateThreshold is the largest accepted threshold; it covers all bits of a hash.
+	maxDuplicateThreshold = 64
+)
+
+const (
+	// imageWidthHeader is the response header carrying the width of a raw bitmap.
+	imageWidthHeader = "X-Image-Width"
+	// imageHeightHeader is the response header carrying the height of a raw bitmap.
+	imageHeightHeader = "X-Image-Height"
+)
+
+// maxImageScale is the largest upscaling factor accepted for rendered images.
+const maxImageScale = 16
+
+// maxObjectIconSize is the largest width and height, in pixels, of an uploaded object icon.
+const maxObjectIconSize = 128
+
+// csvMimeType is the content type of comma separated value tables.
+const csvMimeType = "text/csv"
+
+// dotMimeType is the content type of graphs in the Graphviz DOT language.
+const dotMimeType = "text/vnd.graphviz"
+
+// mergePatchMimeType is the content type of JSON Merge Patch documents (RFC 7396).
+const mergePatchMimeType = "application/merge-patch+json"
+
+const (
+	// defaultMapTileSize is the number of pixels per tile of a rendered level map.
+	defaultMapTileSize = 4
+	// maxMapTileSize is the largest number of pixels per tile of a rendered level map.
+	maxMapTileSize = 32
+)
+
+// maxCachedLevelIndices is the number of level object indices kept for spatial queries.
+const maxCachedLevelIndices = 16
@@ -0,0 +1,263 @@
+package app
+
+import (
+	model "github.com/inkyblackness/shocked-model"
+)
+
+// TextureDuplicates lists the clusters of textures that look alike.
+type TextureDuplicates struct {
+	Href     string           `json:"href"`
+	Clusters []TextureCluster `json:"clusters"`
+}
+
+// TextureCluster is a group of textures that look alike. Textures are grouped when they are
+// alike directly or through other members, so two members of a cluster may differ more than
+// the threshold.
+type TextureCluster struct {
+	Textures []TextureSimilarity `json:"textures"`
+}
+
+// TextureSimilarity describes how much a texture resembles the member of its cluster it is
+// most alike, given by NearestID. Identical is set if the images of both have the same pixels.
+type TextureSimilarity struct {
+	model.Identifiable
+	NearestID  string  `json:"nearestId"`
+	Similarity float64 `json:"similarity"`
+	Identical  bool    `json:"identical"`
+}
+
+// GameObjectClasses lists all object classes with their subclasses and types.
+type GameObjectClasses struct {
+	Href string            `json:"href"`
+	List []GameObjectClass `json:"list"`
+}
+
+// GameObjectClass describes one object class and its subclasses.
+type GameObjectClass struct {
+	model.Identifiable
+	Name       string               `json:"name"`
+	Subclasses []GameObjectSubclass `json:"subclasses"`
+}
+
+// GameObjectSubclass describes one object subclass and its types.
+type GameObjectSubclass struct {
+	model.Identifiable
+	Types []GameObjectType `json:"types"`
+}
+
+// GameObjectType is the catalogue entry of one object type.
+type GameObjectType struct {
+	model.Identifiable
+	Name string `json:"name"`
+}
+
+// TableErrors is the response for rejected table uploads.
+type TableErrors struct {
+	Errors []TableError `json:"errors"`
+}
+
+// TableError describes a problem in an uploaded table. Row numbers start at 1 for the
+// header; Column is empty for problems concerning a whole row.
+type TableError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// ObjectBitmaps lists all bitmaps of a game object, in the order they are stored.
+type ObjectBitmaps struct {
+	Href string         `json:"href"`
+	List []ObjectBitmap `json:"list"`
+}
+
+// ObjectBitmap is one bitmap of a game object. Role tells what the bitmap is used for:
+// "inventory", "world" (the sprite drawn in the level), "frame" (additional state of the
+// world sprite) or "animation" (animation frame of a critter, following its inventory and world bitmaps).
+type ObjectBitmap struct {
+	model.Image
+	Role string `json:"role"`
+}
+
+// ObjectPlacements lists where a game object is placed in the levels of the archive.
+type ObjectPlacements struct {
+	Href string            `json:"href"`
+	List []ObjectPlacement `json:"list"`
+}
+
+// ObjectPlacement refers to one level object. The inherited Href points to the level object.
+type ObjectPlacement struct {
+	model.Identifiable
+	LevelID int `json:"levelId"`
+	TileX   int `json:"tileX"`
+	TileY   int `json:"tileY"`
+}
+
+// JSONSchema is a subset of a JSON Schema (draft 4) document, sufficient to describe
+// the properties of objects.
+type JSONSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        string                 `json:"type"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Enum        []int64                `json:"enum,omitempty"`
+	Minimum     *int64                 `json:"minimum,omitempty"`
+	Maximum     *int64                 `json:"maximum,omitempty"`
+	MinLength   *int64                 `json:"minLength,omitempty"`
+	MaxLength   *int64                 `json:"maxLength,omitempty"`
+
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"`
+}
+
+// GameObjectFields are the known fields of the data of a game object, by field name.
+// They are described by the "gameObject" part of the object class schema.
+type GameObjectFields map[string]int64
+
+// LevelObjectBatch is a list of operations on the objects of one level.
+type LevelObjectBatch struct {
+	Operations []LevelObjectOperation `json:"operations"`
+}
+
+// LevelObjectOperation is one step of a batch. Op is one of "create", "move", "update" or "delete".
+// Create requires Template; move and update require ID and Properties; delete requires ID.
+// A move only considers the position of the properties.
+type LevelObjectOperation struct {
+	Op         string                       `json:"op"`
+	ID         string                       `json:"id,omitempty"`
+	Template   *model.LevelObjectTemplate   `json:"template,omitempty"`
+	Properties *model.LevelObjectProperties `json:"properties,omitempty"`
+}
+
+// LevelObjectBatchResult lists the object ID affected by each operation, in the order of the batch.
+type LevelObjectBatchResult struct {
+	IDs []string `json:"ids"`
+}
+
+// OperationErrors is the response for rejected or failed batches. RollbackErrors lists the problems
+// of undoing a failed batch, such as deleted objects restored under a new ID; if there are any,
+// the batch is partially applied.
+type OperationErrors struct {
+	Errors         []OperationError `json:"errors"`
+	RollbackErrors []string         `json:"rollbackErrors,omitempty"`
+}
+
+// OperationError describes why an operation of a batch, given by its index, was rejected.
+type OperationError struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// LevelObjectCopy describes which level objects to copy where. The selection is given either by
+// IDs or by an area of tiles. Without a target project, the source project is used.
+// The offset is added to the tile coordinates of each copied object.
+type LevelObjectCopy struct {
+	IDs           []string  `json:"ids,omitempty"`
+	Area          *TileArea `json:"area,omitempty"`
+	TargetProject string    `json:"targetProject,omitempty"`
+	TargetLevel   int       `json:"targetLevel"`
+	OffsetX       int       `json:"offsetX"`
+	OffsetY       int       `json:"offsetY"`
+}
+
+// TileArea is a rectangle of tiles, including both corners.
+type TileArea struct {
+	X0 int `json:"x0"`
+	Y0 int `json:"y0"`
+	X1 int `json:"x1"`
+	Y1 int `json:"y1"`
+}
+
+// LevelObjectCopyResult lists the created copies.
+type LevelObjectCopyResult struct {
+	Objects []CopiedLevelObject `json:"objects"`
+}
+
+// CopiedLevelObject refers to a new level object and the object it was copied from.
+type CopiedLevelObject struct {
+	model.Identifiable
+	SourceID string `json:"sourceId"`
+}
+
+// LevelObjectIssues lists the problems found in the objects of a level.
+type LevelObjectIssues struct {
+	Href string             `json:"href"`
+	List []LevelObjectIssue `json:"list"`
+}
+
+// LevelObjectIssue is a problem with one level object. The inherited Href points to the object.
+// Kind is one of "danglingReference", "danglingLevelLink", "insideSolidTile", "outOfBounds",
+// "duplicateContainment" or "cyclicContainment".
+type LevelObjectIssue struct {
+	model.Identifiable
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// LogicGraph describes which objects of a level activate or contain which other objects.
+type LogicGraph struct {
+	Href  string      `json:"href"`
+	Nodes []LogicNode `json:"nodes"`
+	Edges []LogicEdge `json:"edges"`
+}
+
+// LogicNode is a level object taking part in the logic of a level.
+type LogicNode struct {
+	model.Identifiable
+	Class    int    `json:"class"`
+	Subclass int    `json:"subclass"`
+	Type     int    `json:"type"`
+	Name     string `json:"name"`
+}
+
+// LogicEdge is a reference from one level object to another. Kind is "activates" or "contains";
+// Name is the name of the reference within the class data of the source object.
+type LogicEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// TileRegionCopy describes how to copy an area of tiles. The copy is mirrored first, then rotated
+// clockwise and placed with its lower left corner at the target position. Without a target project,
+// the source project is used. Textures can not be mirrored, so a mirrored copy must not contain open
+// tiles with floor or ceiling texture.
+type TileRegionCopy struct {
+	Area          TileArea `json:"area"`
+	TargetProject string   `json:"targetProject,omitempty"`
+	TargetLevel   int      `json:"targetLevel"`
+	TargetX       int      `json:"targetX"`
+	TargetY       int      `json:"targetY"`
+	Rotation      int      `json:"rotation"`
+	Mirror        string   `json:"mirror,omitempty"`
+}
+
+// TileFill describes a flood fill starting at a tile. All tiles connected to the start tile
+// that share its tile type ("type") or floor height ("height") are changed with the properties,
+// which are a JSON Merge Patch of the tile properties. As properties can not be removed, the
+// patch must not contain null.
+type TileFill struct {
+	X          int         `json:"x"`
+	Y          int         `json:"y"`
+	BoundedBy  string      `json:"boundedBy"`
+	Properties interface{} `json:"properties"`
+}
+
+// TileReplace describes a change of all tiles of a level that match the given partial tile
+// properties. The properties are a JSON Merge Patch of the tile properties, without null.
+type TileReplace struct {
+	Match      interface{} `json:"match"`
+	Properties interface{} `json:"properties"`
+}
+
+// TileChanges lists the tiles that were changed by an operation.
+type TileChanges struct {
+	Tiles []TileCoordinate `json:"tiles"`
+}
+
+// TileCoordinate is the position of a tile in a level.
+type TileCoordinate struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
@@ -0,0 +1,217 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+
+	resimage "github.com/inkyblackness/res/image"
+	core "github.com/inkyblackness/shocked-core"
+	model "github.com/inkyblackness/shocked-model"
+)
+
+var (
+	mapSolidColor  = color.RGBA{R: 0, G: 0, B: 0, A: 255}
+	mapFloorColor  = color.RGBA{R: 128, G: 128, B: 128, A: 255}
+	mapGridColor   = color.RGBA{R: 64, G: 64, B: 64, A: 255}
+	mapObjectColor = color.RGBA{R: 255, G: 0, B: 0, A: 255}
+)
+
+// mapTextureSize is the size of the floor textures drawn into a level map.
+const mapTextureSize = model.TextureSize("small")
+
+// levelMapOptions describe how a level map is rendered.
+type levelMapOptions struct {
+	tileSize int
+	grid     bool
+	textures bool
+	objects  bool
+	heights  bool
+}
+
+// levelMapOptionsFromRequest reads the query parameters "scale", "grid", "textures", "objects" and "heights".
+func levelMapOptionsFromRequest(request *restful.Request) (options levelMapOptions, err error) {
+	options.tileSize = defaultMapTileSize
+
+	if scaleParam := request.QueryParameter("scale"); scaleParam != "" {
+		options.tileSize, err = strconv.Atoi(scaleParam)
+		if (err == nil) && ((options.tileSize < 1) || (options.tileSize > maxMapTileSize)) {
+			err = fmt.Errorf("Scale must be between 1 and %d", maxMapTileSize)
+		}
+	}
+	flags := map[string]*bool{
+		"grid": &options.grid, "textures": &options.textures, "objects": &options.objects, "heights": &options.heights}
+	for name, flag := range flags {
+		if text := request.QueryParameter(name); (err == nil) && (text != "") {
+			*flag, err = strconv.ParseBool(text)
+		}
+	}
+
+	return
+}
+
+// levelMap renders the tiles of a level from above. North is at the top of the image;
+// the tile with y = 0 is therefore drawn in the bottom row.
+type levelMap struct {
+	options       levelMapOptions
+	palette       color.Palette
+	level         *core.Level
+	levelTextures []int
+	textures      *core.Textures
+	floors        map[int]resimage.Bitmap
+}
+
+// renderLevelMap returns the map of the level. Floor textures are drawn with the palette.
+func renderLevelMap(project *core.Project, level *core.Level, options levelMapOptions, palette color.Palette) *image.RGBA {
+	renderer := &levelMap{
+		options:       options,
+		palette:       palette,
+		level:         level,
+		levelTextures: level.Textures(),
+		textures:      project.Textures(),
+		floors:        map[int]resimage.Bitmap{}}
+	size := levelTileCount * options.tileSize
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < levelTileCount; y++ {
+		for x := 0; x < levelTileCount; x++ {
+			renderer.drawTile(img, x, y)
+		}
+	}
+	if options.objects {
+		for _, object := range level.Objects() {
+			renderer.drawObject(img, object.BaseProperties)
+		}
+	}
+
+	return img
+}
+
+// drawTile draws one tile, including its grid lines.
+func (renderer *levelMap) drawTile(img *image.RGBA, x, y int) {
+	tileSize := renderer.options.tileSize
+	properties := renderer.level.TileProperties(x, y)
+	left := x * tileSize
+	top := (levelTileCount - 1 - y) * tileSize
+	floor := renderer.floorTexture(properties)
+	shade := 1.0
+
+	if renderer.options.heights && (properties.FloorHeight != nil) {
+		shade = 0.4 + 0.6*float64(*properties.FloorHeight)/float64(maxTileHeight)
+	}
+	for fromNorth := 0; fromNorth < tileSize; fromNorth++ {
+		for fromWest := 0; fromWest < tileSize; fromWest++ {
+			var pixel color.RGBA
+
+			switch {
+			case renderer.options.grid && ((fromNorth == 0) || (fromWest == 0)):
+				pixel = mapGridColor
+			case !isOpenTilePixel(properties.Type, fromWest, fromNorth, tileSize):
+				pixel = mapSolidColor
+			default:
+				pixel = shadedColor(renderer.floorColor(floor, properties, fromWest, fromNorth), shade)
+			}
+			img.SetRGBA(left+fromWest, top+fromNorth, pixel)
+		}
+	}
+}
+
+// floorTexture returns the floor texture of the tile, or nil if textures are not drawn
+// or the tile refers to an unknown texture.
+func (renderer *levelMap) floorTexture(properties model.TileProperties) resimage.Bitmap {
+	if !renderer.options.textures || (properties.RealWorld == nil) || (properties.RealWorld.FloorTexture == nil) {
+		return nil
+	}
+	index := *properties.RealWorld.FloorTexture
+	if (index < 0) || (index >= len(renderer.levelTextures)) {
+		return nil
+	}
+	textureID := renderer.levelTextures[index]
+	bmp, cached := renderer.floors[textureID]
+	if !cached {
+		bmp = renderer.textures.Image(textureID, mapTextureSize)
+		renderer.floors[textureID] = bmp
+	}
+
+	return bmp
+}
+
+// floorColor returns the colour of the floor at given pixel within the tile.
+func (renderer *levelMap) floorColor(floor resimage.Bitmap, properties model.TileProperties, fromWest, fromNorth int) color.RGBA {
+	if (floor == nil) || (floor.ImageWidth() == 0) || (floor.ImageHeight() == 0) {
+		return mapFloorColor
+	}
+	tileSize := renderer.options.tileSize
+	u, v := fromWest, fromNorth
+	if properties.RealWorld.FloorTextureRotations != nil {
+		for turn := 0; turn < *properties.RealWorld.FloorTextureRotations%4; turn++ {
+			u, v = v, tileSize-1-u
+		}
+	}
+	textureX := u * int(floor.ImageWidth()) / tileSize
+	textureY := v * int(floor.ImageHeight()) / tileSize
+	index := int(floor.Row(textureY)[textureX])
+	if index >= len(renderer.palette) {
+		return mapFloorColor
+	}
+	red, green, blue, _ := renderer.palette[index].RGBA()
+
+	return color.RGBA{R: uint8(red >> 8), G: uint8(green >> 8), B: uint8(blue >> 8), A: 255}
+}
+
+// drawObject draws a marker at the position of an object.
+func (renderer *levelMap) drawObject(img *image.RGBA, properties model.LevelObjectBaseProperties) {
+	tileSize := renderer.options.tileSize
+	radius := tileSize / 8
+	centerX := properties.TileX*tileSize + properties.FineX*tileSize/256
+	centerY := (levelTileCount-1-properties.TileY)*tileSize + tileSize - 1 - properties.FineY*tileSize/256
+
+	for y := centerY - radius; y <= centerY+radius; y++ {
+		for x := centerX - radius; x <= centerX+radius; x++ {
+			if (image.Point{X: x, Y: y}).In(img.Rect) {
+				img.SetRGBA(x, y, mapObjectColor)
+			}
+		}
+	}
+}
+
+// isOpenTilePixel returns true if the pixel within a tile is part of the open area of the tile type.
+// Diagonal tiles are open in the corner named by their type.
+func isOpenTilePixel(tileType *model.TileType, fromWest, fromNorth, tileSize int) bool {
+	if tileType == nil {
+		return false
+	}
+	name := string(*tileType)
+	last := tileSize - 1
+
+	switch {
+	case name == string(solidTileType):
+		return false
+	case strings.HasPrefix(name, "diagonalOpen"):
+		switch name[len("diagonalOpen"):] {
+		case "SouthEast":
+			return fromWest+fromNorth >= last
+		case "NorthWest":
+			return fromWest+fromNorth <= last
+		case "NorthEast":
+			return fromWest >= fromNorth
+		case "SouthWest":
+			return fromWest <= fromNorth
+		}
+	}
+
+	return true
+}
+
+// shadedColor returns the colour with its brightness scaled by given factor.
+func shadedColor(value color.RGBA, factor float64) color.RGBA {
+	return color.RGBA{
+		R: uint8(float64(value.R) * factor),
+		G: uint8(float64(value.G) * factor),
+		B: uint8(float64(value.B) * factor),
+		A: value.A}
+}
@@ -0,0 +1,202 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+
+	core "github.com/inkyblackness/shocked-core"
+	model "github.com/inkyblackness/shocked-model"
+)
+
+// validateLevelObjectBatch checks all operations of a batch against the current objects of the level.
+func validateLevelObjectBatch(objects []model.LevelObject, batch *LevelObjectBatch) (errors []OperationError) {
+	existing := map[string]*model.LevelObject{}
+	for index := range objects {
+		existing[objects[index].ID] = &objects[index]
+	}
+	deleted := map[string]bool{}
+	reject := func(index int, format string, a ...interface{}) {
+		errors = append(errors, OperationError{Index: index, Message: fmt.Sprintf(format, a...)})
+	}
+
+	for index, operation := range batch.Operations {
+		switch operation.Op {
+		case "create":
+			if operation.Template == nil {
+				reject(index, "Template is required")
+			} else if err := validateLevelObjectTemplate(operation.Template); err != nil {
+				reject(index, "%v", err)
+			}
+		case "move", "update", "delete":
+			object, found := existing[operation.ID]
+			if !found || deleted[operation.ID] {
+				reject(index, "Unknown level object %q", operation.ID)
+			} else if operation.Op == "delete" {
+				deleted[operation.ID] = true
+			} else if operation.Properties == nil {
+				reject(index, "Properties are required")
+			} else {
+				properties := *operation.Properties
+				if operation.Op == "move" {
+					properties = movedLevelObjectProperties(operation.Properties)
+				}
+				if err := validateLevelObjectProperties(object, &properties, false); err != nil {
+					reject(index, "%v", err)
+				}
+			}
+		default:
+			reject(index, "Unknown operation %q", operation.Op)
+		}
+	}
+
+	return
+}
+
+// movedLevelObjectProperties returns only the position of given properties.
+func movedLevelObjectProperties(properties *model.LevelObjectProperties) model.LevelObjectProperties {
+	return model.LevelObjectProperties{
+		TileX: properties.TileX,
+		FineX: properties.FineX,
+		TileY: properties.TileY,
+		FineY: properties.FineY,
+		Z:     properties.Z}
+}
+
+// fullLevelObjectProperties returns the complete state of the object as properties,
+// including a copy of its class data.
+func fullLevelObjectProperties(object *model.LevelObject) model.LevelObjectProperties {
+	base := object.BaseProperties
+
+	return model.LevelObjectProperties{
+		Subclass:  &object.Subclass,
+		Type:      &object.Type,
+		TileX:     &base.TileX,
+		FineX:     &base.FineX,
+		TileY:     &base.TileY,
+		FineY:     &base.FineY,
+		Z:         &base.Z,
+		ClassData: append([]byte(nil), object.ClassData...)}
+}
+
+// levelObjectTemplate returns the template to create a new object like the given one.
+func levelObjectTemplate(object *model.LevelObject) model.LevelObjectTemplate {
+	base := object.BaseProperties
+
+	return model.LevelObjectTemplate{
+		Class:    object.Class,
+		Subclass: object.Subclass,
+		Type:     object.Type,
+		TileX:    base.TileX,
+		FineX:    base.FineX,
+		TileY:    base.TileY,
+		FineY:    base.FineY,
+		Z:        base.Z}
+}
+
+// recreateLevelObject adds a deleted object to the level again, with all of its properties.
+// The level assigns the ID, and nothing guarantees it is the previous one. Should it differ,
+// the object is kept under its new ID and an error is returned, as references to the old ID
+// would otherwise silently point elsewhere.
+func recreateLevelObject(level *core.Level, object *model.LevelObject) error {
+	template := levelObjectTemplate(object)
+	objectID, err := level.AddObject(&template)
+
+	if err == nil {
+		properties := fullLevelObjectProperties(object)
+		err = level.SetObject(objectID, &properties)
+	}
+	if (err == nil) && (fmt.Sprintf("%d", objectID) != object.ID) {
+		err = fmt.Errorf("Object %s was restored as object %d; references to it need to be fixed", object.ID, objectID)
+	}
+
+	return err
+}
+
+// applyLevelObjectBatch executes a validated batch. Deletions are executed last so that they can
+// not be affected by failures of other operations, and so that no created object takes the place
+// of a deleted one. Should any operation fail, the batch is undone: created objects are removed
+// first, which frees the places they took; changed objects are put back to their complete previous
+// state, including class data; and deleted objects are created again.
+//
+// The undo is not atomic: the level, not the batch, assigns the IDs of created objects, so a deleted
+// object may come back under another ID, leaving references to it dangling. This, and any other
+// problem while undoing, is returned in rollbackErrors; the level is then left partially changed.
+// The caller must hold the lock of the level, so that the state to return to is the one the batch
+// was validated against.
+func applyLevelObjectBatch(level *core.Level, batch *LevelObjectBatch) (result LevelObjectBatchResult, failedIndex int, rollbackErrors []string, err error) {
+	existing := map[string]model.LevelObject{}
+	for _, object := range level.Objects() {
+		existing[object.ID] = object
+	}
+	var created []int
+	var changed []string
+	var deleted []string
+	isChanged := map[string]bool{}
+	isDeleted := map[string]bool{}
+	rollback := func() {
+		note := func(restoreErr error) {
+			if restoreErr != nil {
+				rollbackErrors = append(rollbackErrors, restoreErr.Error())
+			}
+		}
+		for _, objectID := range created {
+			note(level.RemoveObject(objectID))
+		}
+		for _, id := range changed {
+			if !isDeleted[id] {
+				object := existing[id]
+				objectID, _ := strconv.Atoi(id)
+				previous := fullLevelObjectProperties(&object)
+				note(level.SetObject(objectID, &previous))
+			}
+		}
+		for index := len(deleted) - 1; index >= 0; index-- {
+			object := existing[deleted[index]]
+			note(recreateLevelObject(level, &object))
+		}
+	}
+
+	result.IDs = make([]string, len(batch.Operations))
+	for index, operation := range batch.Operations {
+		switch operation.Op {
+		case "create":
+			var objectID int
+			objectID, err = level.AddObject(operation.Template)
+			if err == nil {
+				created = append(created, objectID)
+				result.IDs[index] = fmt.Sprintf("%d", objectID)
+			}
+		case "move", "update":
+			objectID, _ := strconv.Atoi(operation.ID)
+			properties := *operation.Properties
+			if operation.Op == "move" {
+				properties = movedLevelObjectProperties(operation.Properties)
+			}
+			if !isChanged[operation.ID] {
+				isChanged[operation.ID] = true
+				changed = append(changed, operation.ID)
+			}
+			err = level.SetObject(objectID, &properties)
+			result.IDs[index] = operation.ID
+		}
+		if err != nil {
+			rollback()
+			return result, index, rollbackErrors, err
+		}
+	}
+	for index, operation := range batch.Operations {
+		if operation.Op == "delete" {
+			objectID, _ := strconv.Atoi(operation.ID)
+			err = level.RemoveObject(objectID)
+			if err != nil {
+				rollback()
+				return result, index, rollbackErrors, err
+			}
+			isDeleted[operation.ID] = true
+			deleted = append(deleted, operation.ID)
+			result.IDs[index] = operation.ID
+		}
+	}
+
+	return
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+
+	core "github.com/inkyblackness/shocked-core"
+	model "github.com/inkyblackness/shocked-model"
+)
+
+// selectLevelObjects returns the objects selected by IDs or area of the copy request.
+func selectLevelObjects(index *levelObjectIndex, request *LevelObjectCopy) ([]model.LevelObject, error) {
+	if (len(request.IDs) > 0) == (request.Area != nil) {
+		return nil, fmt.Errorf("Either ids or area must be given")
+	}
+	if request.Area != nil {
+		area := request.Area
+		query := levelObjectQuery{
+			hasArea: true,
+			minX:    minInt(area.X0, area.X1) * fineUnitsPerTile,
+			minY:    minInt(area.Y0, area.Y1) * fineUnitsPerTile,
+			maxX:    maxInt(area.X0, area.X1)*fineUnitsPerTile + fineUnitsPerTile - 1,
+			maxY:    maxInt(area.Y0, area.Y1)*fineUnitsPerTile + fineUnitsPerTile - 1}
+		return index.find(query), nil
+	}
+
+	byID := map[string]model.LevelObject{}
+	for _, object := range index.objects {
+		byID[object.ID] = object
+	}
+	var selection []model.LevelObject
+	for _, id := range request.IDs {
+		object, found := byID[id]
+		if !found {
+			return nil, fmt.Errorf("Unknown level object %q", id)
+		}
+		selection = append(selection, object)
+	}
+
+	return selection, nil
+}
+
+// copiedLevelObjectTemplate returns the template for a copy of the object, moved by the offset.
+func copiedLevelObjectTemplate(object *model.LevelObject, request *LevelObjectCopy) model.LevelObjectTemplate {
+	base := object.BaseProperties
+
+	return model.LevelObjectTemplate{
+		Class:    object.Class,
+		Subclass: object.Subclass,
+		Type:     object.Type,
+		TileX:    base.TileX + request.OffsetX,
+		FineX:    base.FineX,
+		TileY:    base.TileY + request.OffsetY,
+		FineY:    base.FineY,
+		Z:        base.Z}
+}
+
+// copyLevelObjects creates copies of the selection in the target level. References between
+// copied objects are changed to the copies. Other references are cleared, except for references
+// that do not contain the referenced object, which are kept within the same level. If an object
+// can not be created or its class data can not be set, all copies are removed again.
+func copyLevelObjects(selection []model.LevelObject, request *LevelObjectCopy, target *core.Level,
+	sameLevel bool) (mapping map[string]string, err error) {
+	templates := make([]model.LevelObjectTemplate, len(selection))
+
+	for index := range selection {
+		templates[index] = copiedLevelObjectTemplate(&selection[index], request)
+		if err = validateLevelObjectTemplate(&templates[index]); err != nil {
+			return nil, fmt.Errorf("Object %s: %v", selection[index].ID, err)
+		}
+	}
+
+	mapping = map[string]string{}
+	var created []int
+	for index := range selection {
+		var newID int
+		newID, err = target.AddObject(&templates[index])
+		if err != nil {
+			for _, id := range created {
+				target.RemoveObject(id)
+			}
+			return nil, fmt.Errorf("Object %s: %v", selection[index].ID, err)
+		}
+		created = append(created, newID)
+		mapping[selection[index].ID] = fmt.Sprintf("%d", newID)
+	}
+
+	remap := func(reference levelObjectReference, id int) int {
+		if newID, copied := mapping[fmt.Sprintf("%d", id)]; copied {
+			result, _ := strconv.Atoi(newID)
+			return result
+		}
+		if sameLevel && !reference.contained {
+			return id
+		}
+		return 0
+	}
+	for index := range selection {
+		if len(selection[index].ClassData) == 0 {
+			continue
+		}
+		properties := model.LevelObjectProperties{ClassData: remappedClassData(&selection[index], remap)}
+		if err = target.SetObject(created[index], &properties); err != nil {
+			for _, id := range created {
+				target.RemoveObject(id)
+			}
+			return nil, fmt.Errorf("Object %s: %v", selection[index].ID, err)
+		}
+	}
+
+	return mapping, nil
+}
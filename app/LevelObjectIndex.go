@@ -0,0 +1,233 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/emicklei/go-restful"
+
+	model "github.com/inkyblackness/shocked-model"
+)
+
+const (
+	levelTileCount   = 64
+	fineUnitsPerTile = 256
+)
+
+// levelObjectIndex is a snapshot of the objects of a level, bucketed by the tile they are in.
+type levelObjectIndex struct {
+	objects []model.LevelObject
+	tiles   [levelTileCount][levelTileCount][]int
+}
+
+// levelObjectQuery describes a spatial query; all coordinates are in fine units.
+type levelObjectQuery struct {
+	hasArea                bool
+	minX, minY, maxX, maxY int
+
+	hasRadius        bool
+	centerX, centerY int
+	radius           int
+
+	class, subclass *int
+}
+
+// levelObjectQueryFromRequest reads the query parameters of a spatial query.
+// Area (x0, y0, x1, y1) and circle (cx, cy, radius) are given in tiles, unless units is "fine".
+// Tile areas include the given tiles completely; tile centers are in the middle of the tile.
+func levelObjectQueryFromRequest(request *restful.Request) (query levelObjectQuery, err error) {
+	parameters := map[string]*int{}
+	for _, name := range []string{"x0", "y0", "x1", "y1", "cx", "cy", "radius", "class", "subclass"} {
+		if text := request.QueryParameter(name); text != "" {
+			value, parseErr := strconv.Atoi(text)
+			if parseErr != nil {
+				return query, fmt.Errorf("Parameter %s is not a number", name)
+			}
+			parameters[name] = &value
+		}
+	}
+	units := request.QueryParameter("units")
+	if (units != "") && (units != "tile") && (units != "fine") {
+		return query, fmt.Errorf("Unknown units %q", units)
+	}
+	toFine := func(value int, end bool) int {
+		if units == "fine" {
+			return value
+		}
+		if end {
+			return value*fineUnitsPerTile + fineUnitsPerTile - 1
+		}
+		return value * fineUnitsPerTile
+	}
+
+	x0, y0, x1, y1 := parameters["x0"], parameters["y0"], parameters["x1"], parameters["y1"]
+	if (x0 != nil) || (y0 != nil) || (x1 != nil) || (y1 != nil) {
+		if (x0 == nil) || (y0 == nil) || (x1 == nil) || (y1 == nil) {
+			return query, fmt.Errorf("Area requires all of x0, y0, x1 and y1")
+		}
+		query.hasArea = true
+		query.minX, query.maxX = toFine(minInt(*x0, *x1), false), toFine(maxInt(*x0, *x1), true)
+		query.minY, query.maxY = toFine(minInt(*y0, *y1), false), toFine(maxInt(*y0, *y1), true)
+	}
+	cx, cy, radius := parameters["cx"], parameters["cy"], parameters["radius"]
+	if (cx != nil) || (cy != nil) || (radius != nil) {
+		if (cx == nil) || (cy == nil) || (radius == nil) {
+			return query, fmt.Errorf("Radius search requires all of cx, cy and radius")
+		}
+		query.hasRadius = true
+		query.centerX, query.centerY, query.radius = *cx, *cy, *radius
+		if units != "fine" {
+			query.centerX = *cx*fineUnitsPerTile + fineUnitsPerTile/2
+			query.centerY = *cy*fineUnitsPerTile + fineUnitsPerTile/2
+			query.radius = *radius * fineUnitsPerTile
+		}
+	}
+	query.class = parameters["class"]
+	query.subclass = parameters["subclass"]
+
+	return
+}
+
+func newLevelObjectIndex(objects []model.LevelObject) *levelObjectIndex {
+	index := &levelObjectIndex{objects: objects}
+
+	for objectIndex, object := range objects {
+		x, y := clampTile(object.BaseProperties.TileX), clampTile(object.BaseProperties.TileY)
+		index.tiles[y][x] = append(index.tiles[y][x], objectIndex)
+	}
+
+	return index
+}
+
+func clampTile(value int) int {
+	if value < 0 {
+		return 0
+	}
+	if value >= levelTileCount {
+		return levelTileCount - 1
+	}
+	return value
+}
+
+func fineX(object *model.LevelObject) int {
+	return object.BaseProperties.TileX*fineUnitsPerTile + object.BaseProperties.FineX
+}
+
+func fineY(object *model.LevelObject) int {
+	return object.BaseProperties.TileY*fineUnitsPerTile + object.BaseProperties.FineY
+}
+
+// find returns all objects matching the query, in the order of the level.
+func (index *levelObjectIndex) find(query levelObjectQuery) []model.LevelObject {
+	minTileX, minTileY, maxTileX, maxTileY := 0, 0, levelTileCount-1, levelTileCount-1
+
+	if query.hasArea {
+		minTileX, minTileY = maxInt(minTileX, query.minX/fineUnitsPerTile), maxInt(minTileY, query.minY/fineUnitsPerTile)
+		maxTileX, maxTileY = minInt(maxTileX, query.maxX/fineUnitsPerTile), minInt(maxTileY, query.maxY/fineUnitsPerTile)
+	}
+	if query.hasRadius {
+		minTileX = maxInt(minTileX, (query.centerX-query.radius)/fineUnitsPerTile)
+		minTileY = maxInt(minTileY, (query.centerY-query.radius)/fineUnitsPerTile)
+		maxTileX = minInt(maxTileX, (query.centerX+query.radius)/fineUnitsPerTile)
+		maxTileY = minInt(maxTileY, (query.centerY+query.radius)/fineUnitsPerTile)
+	}
+
+	matching := make([]bool, len(index.objects))
+	for tileY := minTileY; tileY <= maxTileY; tileY++ {
+		for tileX := minTileX; tileX <= maxTileX; tileX++ {
+			for _, objectIndex := range index.tiles[tileY][tileX] {
+				matching[objectIndex] = query.matches(&index.objects[objectIndex])
+			}
+		}
+	}
+	result := []model.LevelObject{}
+	for objectIndex, match := range matching {
+		if match {
+			result = append(result, index.objects[objectIndex])
+		}
+	}
+
+	return result
+}
+
+func (query levelObjectQuery) matches(object *model.LevelObject) bool {
+	x, y := fineX(object), fineY(object)
+
+	if query.hasArea && ((x < query.minX) || (x > query.maxX) || (y < query.minY) || (y > query.maxY)) {
+		return false
+	}
+	if query.hasRadius {
+		dx, dy := x-query.centerX, y-query.centerY
+		if dx*dx+dy*dy > query.radius*query.radius {
+			return false
+		}
+	}
+	if (query.class != nil) && (object.Class != *query.class) {
+		return false
+	}
+	if (query.subclass != nil) && (object.Subclass != *query.subclass) {
+		return false
+	}
+
+	return true
+}
+
+// levelObjectIndices keeps the object indices of levels until their objects are modified.
+// At most maxCachedLevelIndices are kept; the least recently used index is dropped first.
+type levelObjectIndices struct {
+	mutex   sync.Mutex
+	indices map[string]*levelObjectIndex
+	// keys lists the keys of all kept indices, the most recently used last.
+	keys []string
+}
+
+func newLevelObjectIndices() *levelObjectIndices {
+	return &levelObjectIndices{indices: make(map[string]*levelObjectIndex)}
+}
+
+func levelIndexKey(projectID string, levelID int) string {
+	return fmt.Sprintf("%s/%d", projectID, levelID)
+}
+
+// get returns the index of a level, creating it with the provided function if necessary.
+func (indices *levelObjectIndices) get(projectID string, levelID int, objects func() []model.LevelObject) *levelObjectIndex {
+	indices.mutex.Lock()
+	defer indices.mutex.Unlock()
+	key := levelIndexKey(projectID, levelID)
+	index, existing := indices.indices[key]
+
+	if existing {
+		indices.forget(key)
+	} else {
+		index = newLevelObjectIndex(objects())
+		indices.indices[key] = index
+		if len(indices.keys) >= maxCachedLevelIndices {
+			delete(indices.indices, indices.keys[0])
+			indices.keys = indices.keys[1:]
+		}
+	}
+	indices.keys = append(indices.keys, key)
+
+	return index
+}
+
+// invalidate drops the index of a level. It must be called whenever the objects of the level change.
+func (indices *levelObjectIndices) invalidate(projectID string, levelID int) {
+	indices.mutex.Lock()
+	defer indices.mutex.Unlock()
+	key := levelIndexKey(projectID, levelID)
+
+	delete(indices.indices, key)
+	indices.forget(key)
+}
+
+// forget removes the given key from the order of use.
+func (indices *levelObjectIndices) forget(key string) {
+	for position, kept := range indices.keys {
+		if kept == key {
+			indices.keys = append(indices.keys[:position], indices.keys[position+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	model "github.com/inkyblackness/shocked-model"
+)
+
+// levelObjectReference describes a 16-bit object ID stored in the class data of a level object.
+// Contained references put the referenced object into the referencing one; activating references
+// name the objects that are triggered by the referencing one. Level links hold the ID of a level
+// instead of an object. References that depend on the action type of the object list the action
+// types they are valid for.
+type levelObjectReference struct {
+	name        string
+	offset      int
+	contained   bool
+	activates   bool
+	levelLink   bool
+	actionTypes []byte
+}
+
+// Fixtures (class 9) and traps (class 12) carry an action at the start of their class data, in the
+// layout of the fixture and trap records of the game's level archives: the action type (1 byte),
+// a destroy count (1 byte) and a comparator (4 bytes), followed by four parameters of 4 bytes each.
+// How the parameters are used depends on the action type; object IDs are stored in their lower 16 bits.
+
+// actionTypeOffset is the position of the action type within the class data of triggering objects.
+const actionTypeOffset = 0
+
+const (
+	// teleportActionType moves the player, possibly to another level.
+	teleportActionType = 1
+	// cloneActionType clones or moves another object.
+	cloneActionType = 3
+	// propagateActionType is the action that triggers up to four other objects.
+	propagateActionType = 6
+	// choiceActionType triggers one of two objects, depending on a game variable.
+	choiceActionType = 11
+	// changeStateActionType toggles the state of another object, such as opening a door.
+	changeStateActionType = 15
+	// changeTypeActionType changes the type of another object.
+	changeTypeActionType = 20
+)
+
+// actionParameterOffset returns the position of an action parameter (0 to 3) within the class data.
+func actionParameterOffset(parameter int) int {
+	return 6 + parameter*4
+}
+
+// actionReference returns the reference of an object ID in an action parameter.
+func actionReference(name string, actionType byte, parameter int) levelObjectReference {
+	return levelObjectReference{
+		name:        name,
+		offset:      actionParameterOffset(parameter),
+		activates:   true,
+		actionTypes: []byte{actionType}}
+}
+
+// actionTargets returns the references of the parameters of actions.
+func actionTargets() []levelObjectReference {
+	var references []levelObjectReference
+
+	for parameter := 0; parameter < 4; parameter++ {
+		references = append(references, actionReference(fmt.Sprintf("target%d", parameter+1), propagateActionType, parameter))
+	}
+	references = append(references,
+		actionReference("choice1", choiceActionType, 1),
+		actionReference("choice2", choiceActionType, 2),
+		actionReference("stateTarget", changeStateActionType, 0),
+		actionReference("cloneSource", cloneActionType, 0),
+		actionReference("typeTarget", changeTypeActionType, 0),
+		levelObjectReference{name: "destinationLevel", offset: actionParameterOffset(3), levelLink: true,
+			actionTypes: []byte{teleportActionType}})
+
+	return references
+}
+
+// levelObjectReferences lists the known object references in class data, per class ID.
+var levelObjectReferences = map[int][]levelObjectReference{
+	9:  actionTargets(),
+	12: actionTargets(),
+	13: {
+		{name: "contents1", offset: 0, contained: true},
+		{name: "contents2", offset: 2, contained: true}}}
+
+// appliesTo returns true if the reference is valid for the action type of the object.
+func (reference levelObjectReference) appliesTo(object *model.LevelObject) bool {
+	if len(object.ClassData) < reference.offset+2 {
+		return false
+	}
+	if len(reference.actionTypes) == 0 {
+		return true
+	}
+	if len(object.ClassData) > actionTypeOffset {
+		for _, actionType := range reference.actionTypes {
+			if object.ClassData[actionTypeOffset] == actionType {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// referencedObjectIDs returns the non-zero object IDs the given object refers to, by reference name.
+func referencedObjectIDs(object *model.LevelObject) map[string]int {
+	result := map[string]int{}
+
+	for _, reference := range levelObjectReferences[object.Class] {
+		if reference.appliesTo(object) && !reference.levelLink {
+			if id := int(binary.LittleEndian.Uint16(object.ClassData[reference.offset:])); id != 0 {
+				result[reference.name] = id
+			}
+		}
+	}
+
+	return result
+}
+
+// referencedLevelIDs returns the IDs of the levels the given object links to, by reference name.
+func referencedLevelIDs(object *model.LevelObject) map[string]int {
+	result := map[string]int{}
+
+	for _, reference := range levelObjectReferences[object.Class] {
+		if reference.appliesTo(object) && reference.levelLink {
+			result[reference.name] = int(binary.LittleEndian.Uint16(object.ClassData[reference.offset:]))
+		}
+	}
+
+	return result
+}
+
+// remappedClassData returns a copy of the class data of the object with all references replaced
+// according to the mapping function. The function returns the new ID, or 0 to clear the reference.
+func remappedClassData(object *model.LevelObject, mapping func(reference levelObjectReference, id int) int) []byte {
+	data := append([]byte(nil), object.ClassData...)
+
+	for _, reference := range levelObjectReferences[object.Class] {
+		if reference.appliesTo(object) && !reference.levelLink {
+			id := int(binary.LittleEndian.Uint16(data[reference.offset:]))
+			if id != 0 {
+				binary.LittleEndian.PutUint16(data[reference.offset:], uint16(mapping(reference, id)))
+			}
+		}
+	}
+
+	return data
+}
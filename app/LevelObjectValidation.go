@@ -0,0 +1,114 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+
+	model "github.com/inkyblackness/shocked-model"
+)
+
+const solidTileType = model.TileType("solid")
+
+// levelObjectIssue is a problem found by validateLevelObjects.
+type levelObjectIssue struct {
+	objectID string
+	kind     string
+	message  string
+}
+
+// validateLevelObjects checks the references and positions of all objects of a level.
+// The tile function returns the properties of the tile at given coordinates; levelIDs lists
+// the levels of the archive that level links may refer to.
+func validateLevelObjects(objects []model.LevelObject, tile func(x, y int) model.TileProperties, levelIDs []int) (issues []levelObjectIssue) {
+	byID := map[int]*model.LevelObject{}
+	for index := range objects {
+		id, _ := strconv.Atoi(objects[index].ID)
+		byID[id] = &objects[index]
+	}
+	levels := map[int]bool{}
+	for _, levelID := range levelIDs {
+		levels[levelID] = true
+	}
+	containers := map[int]string{}
+	report := func(object *model.LevelObject, kind string, format string, a ...interface{}) {
+		issues = append(issues, levelObjectIssue{objectID: object.ID, kind: kind, message: fmt.Sprintf(format, a...)})
+	}
+
+	for index := range objects {
+		object := &objects[index]
+		x, y := object.BaseProperties.TileX, object.BaseProperties.TileY
+
+		if (x < 0) || (x >= levelTileCount) || (y < 0) || (y >= levelTileCount) {
+			report(object, "outOfBounds", "Object is placed outside the map at tile %d/%d", x, y)
+		} else if tileType := tile(x, y).Type; (tileType != nil) && (*tileType == solidTileType) {
+			report(object, "insideSolidTile", "Object is placed inside solid tile %d/%d", x, y)
+		}
+
+		for name, levelID := range referencedLevelIDs(object) {
+			if !levels[levelID] {
+				report(object, "danglingLevelLink", "Reference %s points to missing level %d", name, levelID)
+			}
+		}
+		for _, reference := range levelObjectReferences[object.Class] {
+			referencedID, set := referencedObjectIDs(object)[reference.name]
+			if !set {
+				continue
+			}
+			if _, exists := byID[referencedID]; !exists {
+				report(object, "danglingReference", "Reference %s points to missing object %d", reference.name, referencedID)
+			} else if reference.contained {
+				if previous, contained := containers[referencedID]; contained && (previous != object.ID) {
+					report(object, "duplicateContainment", "Object %d is also contained in object %s", referencedID, previous)
+				} else {
+					containers[referencedID] = object.ID
+				}
+			}
+		}
+	}
+
+	for index := range objects {
+		object := &objects[index]
+		if cycleStart, cyclic := containmentCycle(object, byID); cyclic {
+			report(object, "cyclicContainment", "Object contains itself through object %d", cycleStart)
+		}
+	}
+
+	return
+}
+
+// containmentCycle follows the contents of the object and returns true if it reaches the object again.
+// The returned ID is the first object within the cycle.
+func containmentCycle(start *model.LevelObject, byID map[int]*model.LevelObject) (int, bool) {
+	startID, _ := strconv.Atoi(start.ID)
+	visited := map[int]bool{}
+	pending := []int{}
+
+	for _, reference := range levelObjectReferences[start.Class] {
+		if id, set := referencedObjectIDs(start)[reference.name]; set && reference.contained {
+			pending = append(pending, id)
+		}
+	}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		if id == startID {
+			return id, true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		if object, exists := byID[id]; exists {
+			for _, reference := range levelObjectReferences[object.Class] {
+				if next, set := referencedObjectIDs(object)[reference.name]; set && reference.contained {
+					if next == startID {
+						return id, true
+					}
+					pending = append(pending, next)
+				}
+			}
+		}
+	}
+
+	return 0, false
+}
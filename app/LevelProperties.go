@@ -0,0 +1,105 @@
+package app
+
+import (
+	"fmt"
+
+	core "github.com/inkyblackness/shocked-core"
+	model "github.com/inkyblackness/shocked-model"
+)
+
+const (
+	maxHeightShift = 7
+	maxTileHeight  = 31
+)
+
+// validateLevelProperties checks new properties of a level against the current ones.
+func validateLevelProperties(current, properties *model.LevelProperties) error {
+	if (properties.HeightShift < 0) || (properties.HeightShift > maxHeightShift) {
+		return fmt.Errorf("Height shift must be between 0 and %d", maxHeightShift)
+	}
+	if properties.CyberspaceFlag != current.CyberspaceFlag {
+		return fmt.Errorf("Cyberspace flag can not be changed; tiles and objects would be interpreted differently")
+	}
+
+	return nil
+}
+
+// rescaledHeight converts a tile height from one height shift to another. The height of one unit
+// halves with each step of the height shift, so the same physical height needs twice the units.
+// ok is false if the height can not be represented with the new shift.
+func rescaledHeight(height model.HeightUnit, oldShift, newShift int) (result model.HeightUnit, ok bool) {
+	value := int(height)
+
+	if newShift >= oldShift {
+		value <<= uint(newShift - oldShift)
+	} else {
+		divisor := 1 << uint(oldShift-newShift)
+		if value%divisor != 0 {
+			return height, false
+		}
+		value /= divisor
+	}
+
+	return model.HeightUnit(value), value <= maxTileHeight
+}
+
+// rescaledTileProperties returns the properties of a tile with floor, ceiling and slope height
+// converted from one height shift to another. ok is false if a height can not be represented.
+func rescaledTileProperties(properties model.TileProperties, oldShift, newShift int) (result model.TileProperties, ok bool) {
+	result = properties
+	ok = true
+
+	for _, entry := range []**model.HeightUnit{&result.FloorHeight, &result.CeilingHeight, &result.SlopeHeight} {
+		if *entry != nil {
+			height, heightOk := rescaledHeight(**entry, oldShift, newShift)
+			*entry = &height
+			ok = ok && heightOk
+		}
+	}
+
+	return
+}
+
+// rescaledTileHeights returns the height properties of all tiles of the level, converted to the new
+// height shift so that the physical heights stay the same. An error names the first tile that
+// can not be converted, and how many tiles are affected in total.
+func rescaledTileHeights(level *core.Level, oldShift, newShift int) (tiles [][]model.TileProperties, err error) {
+	failed := 0
+	firstX, firstY := 0, 0
+
+	tiles = make([][]model.TileProperties, levelTileCount)
+	for y := 0; y < levelTileCount; y++ {
+		tiles[y] = make([]model.TileProperties, levelTileCount)
+		for x := 0; x < levelTileCount; x++ {
+			current := level.TileProperties(x, y)
+			converted := &tiles[y][x]
+			tileOk := true
+
+			for _, pair := range []struct {
+				from *model.HeightUnit
+				to   **model.HeightUnit
+			}{
+				{current.FloorHeight, &converted.FloorHeight},
+				{current.CeilingHeight, &converted.CeilingHeight},
+				{current.SlopeHeight, &converted.SlopeHeight}} {
+				if pair.from != nil {
+					height, ok := rescaledHeight(*pair.from, oldShift, newShift)
+					*pair.to = &height
+					tileOk = tileOk && ok
+				}
+			}
+			if !tileOk {
+				if failed == 0 {
+					firstX, firstY = x, y
+				}
+				failed++
+			}
+		}
+	}
+	if failed > 0 {
+		err = fmt.Errorf("Heights of %d tile(s) can not be kept with height shift %d, first at tile %d/%d",
+			failed, newShift, firstX, firstY)
+	}
+
+	return
+}
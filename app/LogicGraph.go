@@ -0,0 +1,78 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	model "github.com/inkyblackness/shocked-model"
+)
+
+// buildLogicGraph collects all objects that reference or are referenced by other objects.
+// The name function provides the display name of an object.
+func buildLogicGraph(objects []model.LevelObject, name func(object *model.LevelObject) string) (graph LogicGraph) {
+	byID := map[string]*model.LevelObject{}
+	for index := range objects {
+		byID[objects[index].ID] = &objects[index]
+	}
+	used := map[string]bool{}
+
+	graph.Nodes = []LogicNode{}
+	graph.Edges = []LogicEdge{}
+	for index := range objects {
+		object := &objects[index]
+		ids := referencedObjectIDs(object)
+
+		for _, reference := range levelObjectReferences[object.Class] {
+			id, set := ids[reference.name]
+			if !set || !(reference.activates || reference.contained) {
+				continue
+			}
+			edge := LogicEdge{From: object.ID, To: strconv.Itoa(id), Kind: "activates", Name: reference.name}
+			if reference.contained {
+				edge.Kind = "contains"
+			}
+			graph.Edges = append(graph.Edges, edge)
+			used[edge.From] = true
+			used[edge.To] = true
+		}
+	}
+	for index := range objects {
+		object := &objects[index]
+		if used[object.ID] {
+			var node LogicNode
+			node.ID = object.ID
+			node.Class, node.Subclass, node.Type = object.Class, object.Subclass, object.Type
+			node.Name = name(object)
+			graph.Nodes = append(graph.Nodes, node)
+		}
+	}
+
+	return
+}
+
+// writeLogicGraphDot writes the graph in the Graphviz DOT language. Edges to unknown objects
+// are drawn to nodes marked as missing.
+func writeLogicGraphDot(writer io.Writer, graph *LogicGraph) {
+	known := map[string]bool{}
+
+	fmt.Fprintf(writer, "digraph logic {\n")
+	fmt.Fprintf(writer, "\tnode [shape=box];\n")
+	for _, node := range graph.Nodes {
+		known[node.ID] = true
+		fmt.Fprintf(writer, "\t\"%s\" [label=%q, URL=%q];\n", node.ID,
+			fmt.Sprintf("%s: %s\n%d/%d/%d", node.ID, node.Name, node.Class, node.Subclass, node.Type), node.Href)
+	}
+	for _, edge := range graph.Edges {
+		if !known[edge.To] {
+			known[edge.To] = true
+			fmt.Fprintf(writer, "\t\"%s\" [label=%q, color=red];\n", edge.To, edge.To+": missing")
+		}
+		style := "solid"
+		if edge.Kind == "contains" {
+			style = "dashed"
+		}
+		fmt.Fprintf(writer, "\t\"%s\" -> \"%s\" [label=%q, style=%s];\n", edge.From, edge.To, edge.Name, style)
+	}
+	fmt.Fprintf(writer, "}\n")
+}
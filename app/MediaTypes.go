@@ -0,0 +1,76 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+)
+
+// acceptedMediaRange is one entry of an Accept header.
+type acceptedMediaRange struct {
+	mediaType string
+	subtype   string
+	quality   float64
+}
+
+// parseAccept returns the media ranges of an Accept header (RFC 7231, section 5.3.2).
+// Entries that can not be parsed are skipped; a missing or invalid quality counts as 1.
+func parseAccept(header string) []acceptedMediaRange {
+	var ranges []acceptedMediaRange
+
+	for _, entry := range strings.Split(header, ",") {
+		parts := strings.Split(entry, ";")
+		types := strings.SplitN(strings.ToLower(strings.TrimSpace(parts[0])), "/", 2)
+		if (len(types) != 2) || (types[0] == "") || (types[1] == "") {
+			continue
+		}
+		mediaRange := acceptedMediaRange{mediaType: types[0], subtype: types[1], quality: 1.0}
+		for _, parameter := range parts[1:] {
+			nameValue := strings.SplitN(strings.TrimSpace(parameter), "=", 2)
+			if (len(nameValue) == 2) && (strings.ToLower(nameValue[0]) == "q") {
+				if quality, err := strconv.ParseFloat(nameValue[1], 64); (err == nil) && (quality >= 0) && (quality <= 1) {
+					mediaRange.quality = quality
+				}
+			}
+		}
+		ranges = append(ranges, mediaRange)
+	}
+
+	return ranges
+}
+
+// preferredMediaType returns the offered media type the Accept header prefers. The quality of an
+// offered type is taken from the most specific matching range; of equally preferred types, the
+// first one offered is taken. Without an Accept header, the first type is returned. An empty
+// string is returned if the header accepts none of the offered types.
+func preferredMediaType(header string, offered ...string) string {
+	if strings.TrimSpace(header) == "" {
+		return offered[0]
+	}
+	ranges := parseAccept(header)
+	result := ""
+	resultQuality := 0.0
+
+	for _, offer := range offered {
+		types := strings.SplitN(strings.ToLower(offer), "/", 2)
+		quality, specificity := 0.0, -1
+		for _, mediaRange := range ranges {
+			rangeSpecificity := -1
+			switch {
+			case (mediaRange.mediaType == types[0]) && (mediaRange.subtype == types[1]):
+				rangeSpecificity = 2
+			case (mediaRange.mediaType == types[0]) && (mediaRange.subtype == "*"):
+				rangeSpecificity = 1
+			case (mediaRange.mediaType == "*") && (mediaRange.subtype == "*"):
+				rangeSpecificity = 0
+			}
+			if rangeSpecificity > specificity {
+				quality, specificity = mediaRange.quality, rangeSpecificity
+			}
+		}
+		if quality > resultQuality {
+			result, resultQuality = offer, quality
+		}
+	}
+
+	return result
+}
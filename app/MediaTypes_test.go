@@ -0,0 +1,34 @@
+package app
+
+import "testing"
+
+func TestPreferredMediaType(t *testing.T) {
+	offered := []string{"application/json", "application/xml", "application/octet-stream"}
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/octet-stream", "application/octet-stream"},
+		{"Application/Octet-Stream", "application/octet-stream"},
+		{"application/octet-stream;q=0", ""},
+		{"application/octet-stream;q=0, */*", "application/json"},
+		{"application/octet-stream;q=0, */*;q=0.1", "application/json"},
+		{"application/json;q=0.5, application/octet-stream", "application/octet-stream"},
+		{"application/json, application/octet-stream;q=0.9", "application/json"},
+		{"application/*;q=0.2, application/octet-stream;q=0.8", "application/octet-stream"},
+		{"application/*", "application/json"},
+		{"image/png", ""},
+		{"text/html, application/xml;q=0.9", "application/xml"},
+		{"application/octet-stream; q=1.0; charset=x", "application/octet-stream"},
+		{"application/octet-stream;q=nonsense", "application/octet-stream"},
+		{"garbage, application/octet-stream", "application/octet-stream"},
+	}
+
+	for _, tc := range tests {
+		if result := preferredMediaType(tc.accept, offered...); result != tc.expected {
+			t.Errorf("Accept %q should select %q, selected %q", tc.accept, tc.expected, result)
+		}
+	}
+}
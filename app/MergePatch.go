@@ -0,0 +1,115 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// readMergePatch reads a JSON Merge Patch document (RFC 7396).
+func readMergePatch(reader io.Reader) (patch interface{}, err error) {
+	err = json.NewDecoder(reader).Decode(&patch)
+	return
+}
+
+// mergePatch applies the patch to the target as described in RFC 7396; Members of patch objects
+// replace those of the target, null removes them, and anything else replaces the target.
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, patchIsObject := patch.(map[string]interface{})
+	if !patchIsObject {
+		return patch
+	}
+	targetObject, targetIsObject := target.(map[string]interface{})
+	if !targetIsObject {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergePatch(targetObject[key], value)
+		}
+	}
+
+	return targetObject
+}
+
+// applyMergePatch applies the patch to the JSON representation of current and stores
+// the result in patched. current and patched may refer to the same value.
+// Unlike RFC 7396, the patch may not contain null: the properties stored by the core
+// treat a missing member as unchanged, so a removal could not take effect.
+func applyMergePatch(current interface{}, patch interface{}, patched interface{}) error {
+	var target interface{}
+	var data []byte
+	err := checkMergePatchNulls(patch, "")
+
+	if err == nil {
+		data, err = json.Marshal(current)
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &target)
+	}
+	if err == nil {
+		data, err = json.Marshal(mergePatch(target, patch))
+	}
+	if err == nil {
+		err = json.Unmarshal(data, patched)
+	}
+
+	return err
+}
+
+// checkMergePatchNulls returns an error for the first null value found in the patch.
+func checkMergePatchNulls(patch interface{}, path string) error {
+	if patch == nil {
+		if path == "" {
+			return fmt.Errorf("Patch must not be null")
+		}
+		return fmt.Errorf("Member %s is null; properties can not be removed", path)
+	}
+	if patchObject, isObject := patch.(map[string]interface{}); isObject {
+		for key, value := range patchObject {
+			memberPath := key
+			if path != "" {
+				memberPath = path + "." + key
+			}
+			if err := checkMergePatchNulls(value, memberPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonValue returns the generic JSON representation of given value.
+func jsonValue(value interface{}) (result interface{}, err error) {
+	data, err := json.Marshal(value)
+
+	if err == nil {
+		err = json.Unmarshal(data, &result)
+	}
+
+	return
+}
+
+// matchesPattern returns true if all members given in the pattern have the same value in the
+// JSON value. Nested objects are compared the same way; other values must be equal.
+func matchesPattern(value interface{}, pattern interface{}) bool {
+	patternObject, patternIsObject := pattern.(map[string]interface{})
+	if !patternIsObject {
+		return reflect.DeepEqual(value, pattern)
+	}
+	valueObject, valueIsObject := value.(map[string]interface{})
+	if !valueIsObject {
+		return false
+	}
+	for key, member := range patternObject {
+		if !matchesPattern(valueObject[key], member) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"github.com/inkyblackness/res"
+)
+
+// objectClassInfo describes the static layout of one object class of the game.
+type objectClassInfo struct {
+	name string
+	// typeCounts lists the number of types per subclass.
+	typeCounts []int
+	// classDataSize is the number of bytes of class specific data of level objects.
+	classDataSize int
+}
+
+// objectClasses lists all object classes of the game, indexed by class ID.
+var objectClasses = []objectClassInfo{
+	{name: "Weapons", typeCounts: []int{5, 2, 2, 2, 3, 2}, classDataSize: 2},
+	{name: "Ammunition", typeCounts: []int{2, 2, 3, 2, 2, 2, 2}, classDataSize: 0},
+	{name: "Projectiles", typeCounts: []int{6, 16, 2}, classDataSize: 34},
+	{name: "Explosives", typeCounts: []int{5, 3}, classDataSize: 6},
+	{name: "Patches", typeCounts: []int{7}, classDataSize: 0},
+	{name: "Hardware", typeCounts: []int{5, 10}, classDataSize: 1},
+	{name: "Software", typeCounts: []int{7, 3, 4, 5, 3}, classDataSize: 3},
+	{name: "Scenery", typeCounts: []int{9, 10, 11, 4, 9, 8, 16, 10}, classDataSize: 10},
+	{name: "Items", typeCounts: []int{8, 10, 15, 6, 12, 12, 9, 8}, classDataSize: 10},
+	{name: "Panels", typeCounts: []int{9, 7, 3, 11, 2, 3}, classDataSize: 24},
+	{name: "Barriers", typeCounts: []int{10, 9, 7, 5, 10}, classDataSize: 8},
+	{name: "Animations", typeCounts: []int{9, 11, 14}, classDataSize: 4},
+	{name: "Markers", typeCounts: []int{13, 1, 5}, classDataSize: 22},
+	{name: "Containers", typeCounts: []int{3, 3, 4, 8, 13, 7, 8}, classDataSize: 15},
+	{name: "Critters", typeCounts: []int{9, 12, 7, 7, 2}, classDataSize: 40}}
+
+// isValidObjectClass returns true for known class IDs.
+func isValidObjectClass(class int) bool {
+	return (class >= 0) && (class < len(objectClasses))
+}
+
+// isValidObjectSubclass returns true for known class/subclass combinations.
+func isValidObjectSubclass(class, subclass int) bool {
+	return isValidObjectClass(class) && (subclass >= 0) && (subclass < len(objectClasses[class].typeCounts))
+}
+
+// isValidObjectID returns true if the given ID refers to an existing object type.
+func isValidObjectID(objID res.ObjectID) bool {
+	class, subclass, objType := int(objID.Class), int(objID.Subclass), int(objID.Type)
+
+	return isValidObjectSubclass(class, subclass) && (objType < objectClasses[class].typeCounts[subclass])
+}
+
+// objectIDs returns the IDs of all object types of given class and subclass.
+func objectIDs(class, subclass int) []res.ObjectID {
+	var ids []res.ObjectID
+
+	if isValidObjectSubclass(class, subclass) {
+		for objType := 0; objType < objectClasses[class].typeCounts[subclass]; objType++ {
+			ids = append(ids, res.MakeObjectID(res.ObjectClass(class), res.ObjectSubclass(subclass), res.ObjectType(objType)))
+		}
+	}
+
+	return ids
+}
+
+// allObjectIDs returns the IDs of all object types of the game.
+func allObjectIDs() []res.ObjectID {
+	var ids []res.ObjectID
+
+	for class, info := range objectClasses {
+		for subclass := range info.typeCounts {
+			ids = append(ids, objectIDs(class, subclass)...)
+		}
+	}
+
+	return ids
+}
+
+// critterClass is the class ID of critters.
+const critterClass = 14
+
+// objectBitmapRole returns the use of a bitmap of an object, by its index. The object art
+// stores the inventory bitmap first, followed by the world sprite. Further bitmaps are the
+// additional states of the world sprite, or, for critters, their animation frames.
+func objectBitmapRole(objID res.ObjectID, index int) string {
+	switch {
+	case index == 0:
+		return "inventory"
+	case index == 1:
+		return "world"
+	case int(objID.Class) == critterClass:
+		return "animation"
+	}
+
+	return "frame"
+}
@@ -0,0 +1,213 @@
+package app
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	model "github.com/inkyblackness/shocked-model"
+)
+
+// objectDataBlock identifies one of the data blocks of game object properties.
+type objectDataBlock string
+
+const (
+	commonBlock   objectDataBlock = "common"
+	genericBlock  objectDataBlock = "generic"
+	specificBlock objectDataBlock = "specific"
+)
+
+// objectDataField describes a numerical value stored in a data block of game object
+// properties. Values are stored little-endian; size is 1, 2 or 4 bytes.
+type objectDataField struct {
+	name        string
+	description string
+	block       objectDataBlock
+	offset      int
+	size        int
+	signed      bool
+	minimum     int64
+	maximum     int64
+}
+
+// commonObjectFields are the fields of the common block, shared by all classes.
+var commonObjectFields = []objectDataField{
+	{name: "mass", description: "Mass of the object", block: commonBlock, offset: 0, size: 4, signed: true, minimum: 0, maximum: 0x7FFFFFFF},
+	{name: "hitpoints", description: "Default hit points of a new instance", block: commonBlock, offset: 4, size: 2, signed: true, minimum: 0, maximum: 0x7FFF},
+	{name: "armor", description: "Armor value", block: commonBlock, offset: 6, size: 1, minimum: 0, maximum: 0xFF},
+	{name: "renderType", description: "How the object is rendered", block: commonBlock, offset: 7, size: 1, minimum: 0, maximum: 0xFF},
+	{name: "physicsModel", description: "Physics model used for the object", block: commonBlock, offset: 8, size: 1, minimum: 0, maximum: 0xFF},
+	{name: "hardness", description: "Hardness of the object", block: commonBlock, offset: 9, size: 1, minimum: 0, maximum: 0xFF}}
+
+// genericObjectFields are the fields of the generic block, per class ID. The layouts follow the
+// class property structures of the game (GunProp, AmmoProp, CritterProp); bytes not listed here
+// remain accessible through the raw data blocks.
+var genericObjectFields = map[int][]objectDataField{
+	0: {
+		{name: "fireRate", description: "Time between two shots", block: genericBlock, offset: 0, size: 1, minimum: 0, maximum: 0xFF},
+		{name: "ammoType", description: "Usable ammunition (subclass and type)", block: genericBlock, offset: 1, size: 1, minimum: 0, maximum: 0xFF}},
+	1: {
+		{name: "damageType", description: "Bit mask of the damage types", block: genericBlock, offset: 0, size: 4, minimum: 0, maximum: 0xFFFFFFFF},
+		{name: "damageModifier", description: "Base damage of a hit", block: genericBlock, offset: 4, size: 2, signed: true, minimum: 0, maximum: 0x7FFF},
+		{name: "offenseValue", description: "Offense value against the armor of the target", block: genericBlock, offset: 6, size: 1, minimum: 0, maximum: 0xFF},
+		{name: "penetration", description: "Armor penetration", block: genericBlock, offset: 7, size: 1, minimum: 0, maximum: 0xFF},
+		{name: "cartridgeSize", description: "Number of rounds per clip", block: genericBlock, offset: 8, size: 1, minimum: 0, maximum: 0xFF},
+		{name: "bulletMass", description: "Mass of a bullet", block: genericBlock, offset: 9, size: 1, minimum: 0, maximum: 0xFF},
+		{name: "bulletSpeed", description: "Speed of a bullet", block: genericBlock, offset: 10, size: 2, signed: true, minimum: 0, maximum: 0x7FFF},
+		{name: "range", description: "Range of a shot", block: genericBlock, offset: 12, size: 1, minimum: 0, maximum: 0xFF},
+		{name: "recoilForce", description: "Recoil of a shot", block: genericBlock, offset: 13, size: 1, minimum: 0, maximum: 0xFF}},
+	14: {
+		{name: "intelligence", description: "Intelligence of the critter", block: genericBlock, offset: 0, size: 1, minimum: 0, maximum: 0xFF},
+		{name: "attackDamageType", description: "Bit mask of the damage types of the primary attack", block: genericBlock, offset: 1, size: 4, minimum: 0, maximum: 0xFFFFFFFF},
+		{name: "attackDamageModifier", description: "Base damage of the primary attack", block: genericBlock, offset: 5, size: 2, signed: true, minimum: 0, maximum: 0x7FFF},
+		{name: "attackOffenseValue", description: "Offense value of the primary attack", block: genericBlock, offset: 7, size: 1, minimum: 0, maximum: 0xFF},
+		{name: "attackPenetration", description: "Armor penetration of the primary attack", block: genericBlock, offset: 8, size: 1, minimum: 0, maximum: 0xFF}}}
+
+// objectFields returns all known data fields of given object class.
+func objectFields(class int) []objectDataField {
+	fields := make([]objectDataField, 0, len(commonObjectFields)+len(genericObjectFields[class]))
+	fields = append(fields, commonObjectFields...)
+	fields = append(fields, genericObjectFields[class]...)
+
+	return fields
+}
+
+func (field objectDataField) blockData(data *model.ObjectData) []byte {
+	switch field.block {
+	case commonBlock:
+		return data.Common
+	case genericBlock:
+		return data.Generic
+	case specificBlock:
+		return data.Specific
+	}
+	return nil
+}
+
+// value returns the value of the field in given data. ok is false if the data block is too short.
+func (field objectDataField) value(data *model.ObjectData) (value int64, ok bool) {
+	block := field.blockData(data)
+
+	if len(block) >= field.offset+field.size {
+		raw := block[field.offset : field.offset+field.size]
+		ok = true
+		switch field.size {
+		case 1:
+			value = int64(raw[0])
+			if field.signed {
+				value = int64(int8(raw[0]))
+			}
+		case 2:
+			value = int64(binary.LittleEndian.Uint16(raw))
+			if field.signed {
+				value = int64(int16(binary.LittleEndian.Uint16(raw)))
+			}
+		case 4:
+			value = int64(binary.LittleEndian.Uint32(raw))
+			if field.signed {
+				value = int64(int32(binary.LittleEndian.Uint32(raw)))
+			}
+		}
+	}
+
+	return
+}
+
+// setValue stores the given value in the data. The value is not range checked.
+func (field objectDataField) setValue(data *model.ObjectData, value int64) {
+	block := field.blockData(data)
+
+	if len(block) >= field.offset+field.size {
+		raw := block[field.offset : field.offset+field.size]
+		switch field.size {
+		case 1:
+			raw[0] = byte(value)
+		case 2:
+			binary.LittleEndian.PutUint16(raw, uint16(value))
+		case 4:
+			binary.LittleEndian.PutUint32(raw, uint32(value))
+		}
+	}
+}
+
+// validate checks the value of the field in given data against the schema of the field.
+func (field objectDataField) validate(data *model.ObjectData) error {
+	value, ok := field.value(data)
+
+	if !ok {
+		return fmt.Errorf("Field %s is missing in %s data", field.name, field.block)
+	}
+
+	return field.schema().validateInteger(field.name, value)
+}
+
+// validateObjectProperties checks new properties of an object against the current ones.
+// The layout of the data blocks is fixed per class and subclass, so their lengths must not
+// change. Known fields must be within their ranges.
+func validateObjectProperties(class int, current, properties *model.GameObjectProperties) error {
+	blocks := []struct {
+		block          objectDataBlock
+		current, given []byte
+	}{
+		{commonBlock, current.Data.Common, properties.Data.Common},
+		{genericBlock, current.Data.Generic, properties.Data.Generic},
+		{specificBlock, current.Data.Specific, properties.Data.Specific}}
+
+	for _, entry := range blocks {
+		if len(entry.current) != len(entry.given) {
+			return fmt.Errorf("Length of %s data must be %d bytes, is %d", entry.block, len(entry.current), len(entry.given))
+		}
+	}
+	for _, field := range objectFields(class) {
+		if err := field.validate(&properties.Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// objectFieldValues returns the values of all known fields of the data, by field name.
+// Fields that are not available in the data are omitted.
+func objectFieldValues(class int, data *model.ObjectData) GameObjectFields {
+	fields := GameObjectFields{}
+
+	for _, field := range objectFields(class) {
+		if value, ok := field.value(data); ok {
+			fields[field.name] = value
+		}
+	}
+
+	return fields
+}
+
+// setObjectFieldValues validates the field values against the schema of the class and stores
+// them in a copy of the data of the properties.
+func setObjectFieldValues(class int, properties *model.GameObjectProperties, fields GameObjectFields) error {
+	schema := gameObjectSchema(class)
+
+	for name := range fields {
+		if _, known := schema.Properties[name]; !known {
+			return fmt.Errorf("Unknown field %s", name)
+		}
+	}
+	for _, name := range schema.Required {
+		value, given := fields[name]
+		if !given {
+			return fmt.Errorf("Field %s is required", name)
+		}
+		if err := schema.Properties[name].validateInteger(name, value); err != nil {
+			return err
+		}
+	}
+	properties.Data.Common = append([]byte(nil), properties.Data.Common...)
+	properties.Data.Generic = append([]byte(nil), properties.Data.Generic...)
+	properties.Data.Specific = append([]byte(nil), properties.Data.Specific...)
+	for _, field := range objectFields(class) {
+		if _, ok := field.value(&properties.Data); !ok {
+			return fmt.Errorf("Field %s is missing in %s data", field.name, field.block)
+		}
+		field.setValue(&properties.Data, fields[field.name])
+	}
+
+	return nil
+}
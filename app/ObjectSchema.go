@@ -0,0 +1,196 @@
+package app
+
+import (
+	"fmt"
+
+	model "github.com/inkyblackness/shocked-model"
+)
+
+const jsonSchemaDraft = "http://json-schema.org/draft-04/schema#"
+
+func integerSchema(description string, minimum, maximum int64) *JSONSchema {
+	return &JSONSchema{Type: "integer", Description: description, Minimum: &minimum, Maximum: &maximum}
+}
+
+func enumSchema(description string, count int) *JSONSchema {
+	schema := &JSONSchema{Type: "integer", Description: description}
+
+	for value := 0; value < count; value++ {
+		schema.Enum = append(schema.Enum, int64(value))
+	}
+
+	return schema
+}
+
+// schema returns the description of the field value.
+func (field objectDataField) schema() *JSONSchema {
+	return integerSchema(field.description, field.minimum, field.maximum)
+}
+
+// objectClassSchema returns the schema describing the game object and level object
+// properties of given class.
+func objectClassSchema(class int) *JSONSchema {
+	return &JSONSchema{
+		Schema:      jsonSchemaDraft,
+		Title:       objectClasses[class].name,
+		Description: fmt.Sprintf("Properties of objects of class %d", class),
+		Type:        "object",
+		Properties: map[string]*JSONSchema{
+			"gameObject":  gameObjectSchema(class),
+			"levelObject": levelObjectSchema(class)}}
+}
+
+// gameObjectSchema describes the known fields of the data blocks of game object properties,
+// as read and written through the fields resource of a game object.
+func gameObjectSchema(class int) *JSONSchema {
+	additional := false
+	schema := &JSONSchema{
+		Title:                objectClasses[class].name,
+		Description:          "Known fields of the common and generic data of game object properties",
+		Type:                 "object",
+		Properties:           map[string]*JSONSchema{},
+		AdditionalProperties: &additional}
+
+	for _, field := range objectFields(class) {
+		schema.Properties[field.name] = field.schema()
+		schema.Required = append(schema.Required, field.name)
+	}
+
+	return schema
+}
+
+// levelObjectSchema describes new level objects and the properties of existing level objects
+// of given class. The class is only given for new objects, the class data only for existing ones.
+func levelObjectSchema(class int) *JSONSchema {
+	maxTypeCount := 0
+	for _, count := range objectClasses[class].typeCounts {
+		maxTypeCount = maxInt(maxTypeCount, count)
+	}
+
+	return &JSONSchema{
+		Title:       objectClasses[class].name,
+		Description: "Placement and class data of an object in a level",
+		Type:        "object",
+		Properties: map[string]*JSONSchema{
+			"class":     integerSchema("Class of the object", int64(class), int64(class)),
+			"subclass":  enumSchema("Subclass of the object", len(objectClasses[class].typeCounts)),
+			"type":      enumSchema("Type of the object within its subclass", maxTypeCount),
+			"tileX":     integerSchema("Horizontal tile coordinate", 0, 63),
+			"fineX":     integerSchema("Horizontal position within the tile", 0, 255),
+			"tileY":     integerSchema("Vertical tile coordinate", 0, 63),
+			"fineY":     integerSchema("Vertical position within the tile", 0, 255),
+			"z":         integerSchema("Height of the object", 0, 255),
+			"classData": classDataSchema(class)},
+		Required: []string{"tileX", "fineX", "tileY", "fineY"}}
+}
+
+// classDataSchema describes the class data of level objects, a base64 encoded byte array
+// of fixed length per class.
+func classDataSchema(class int) *JSONSchema {
+	size := objectClasses[class].classDataSize
+	encodedLength := int64((size + 2) / 3 * 4)
+	description := fmt.Sprintf("Class data of %d bytes, base64 encoded", size)
+
+	for _, reference := range levelObjectReferences[class] {
+		target := "object"
+		if reference.levelLink {
+			target = "level"
+		}
+		description += fmt.Sprintf("; %s: %s ID at byte %d", reference.name, target, reference.offset)
+	}
+
+	return &JSONSchema{Type: "string", Description: description, MinLength: &encodedLength, MaxLength: &encodedLength}
+}
+
+// validateInteger checks the given value against minimum, maximum and enumeration of the schema.
+func (schema *JSONSchema) validateInteger(name string, value int64) error {
+	if (schema.Minimum != nil) && (value < *schema.Minimum) {
+		return fmt.Errorf("Field %s must be at least %d, is %d", name, *schema.Minimum, value)
+	}
+	if (schema.Maximum != nil) && (value > *schema.Maximum) {
+		return fmt.Errorf("Field %s must be at most %d, is %d", name, *schema.Maximum, value)
+	}
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("Field %s has an unknown value %d", name, value)
+	}
+
+	return nil
+}
+
+// validateLevelObjectTemplate checks a template for a new level object against the schema of its class.
+func validateLevelObjectTemplate(template *model.LevelObjectTemplate) error {
+	if !isValidObjectClass(template.Class) {
+		return fmt.Errorf("Unknown object class %d", template.Class)
+	}
+	schema := levelObjectSchema(template.Class)
+	values := map[string]int{
+		"class":    template.Class,
+		"subclass": template.Subclass,
+		"type":     template.Type,
+		"tileX":    template.TileX,
+		"fineX":    template.FineX,
+		"tileY":    template.TileY,
+		"fineY":    template.FineY,
+		"z":        template.Z}
+
+	for _, name := range []string{"class", "subclass", "type", "tileX", "fineX", "tileY", "fineY", "z"} {
+		if err := schema.Properties[name].validateInteger(name, int64(values[name])); err != nil {
+			return err
+		}
+	}
+	if !isValidObjectSubclass(template.Class, template.Subclass) || (template.Type >= objectClasses[template.Class].typeCounts[template.Subclass]) {
+		return fmt.Errorf("Unknown object type %d/%d/%d", template.Class, template.Subclass, template.Type)
+	}
+
+	return nil
+}
+
+// validateLevelObjectProperties checks new properties of an existing level object of given class.
+// If complete is set, all position fields must be given.
+func validateLevelObjectProperties(object *model.LevelObject, properties *model.LevelObjectProperties, complete bool) error {
+	if !isValidObjectClass(object.Class) {
+		return fmt.Errorf("Unknown object class %d", object.Class)
+	}
+	schema := levelObjectSchema(object.Class)
+	values := []struct {
+		name  string
+		value *int
+	}{
+		{"subclass", properties.Subclass},
+		{"type", properties.Type},
+		{"tileX", properties.TileX},
+		{"fineX", properties.FineX},
+		{"tileY", properties.TileY},
+		{"fineY", properties.FineY},
+		{"z", properties.Z}}
+
+	for _, entry := range values {
+		if entry.value != nil {
+			if err := schema.Properties[entry.name].validateInteger(entry.name, int64(*entry.value)); err != nil {
+				return err
+			}
+		} else if complete && (entry.name != "subclass") && (entry.name != "type") {
+			return fmt.Errorf("Field %s is required", entry.name)
+		}
+	}
+	if size := objectClasses[object.Class].classDataSize; (properties.ClassData != nil) && (len(properties.ClassData) != size) {
+		return fmt.Errorf("Field classData must have %d bytes, has %d", size, len(properties.ClassData))
+	}
+	subclass, objType := object.Subclass, object.Type
+	if properties.Subclass != nil {
+		subclass = *properties.Subclass
+	}
+	if properties.Type != nil {
+		objType = *properties.Type
+	}
+	if !isValidObjectSubclass(object.Class, subclass) || (objType >= objectClasses[object.Class].typeCounts[subclass]) {
+		return fmt.Errorf("Unknown object type %d/%d/%d", object.Class, subclass, objType)
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/inkyblackness/res"
+	model "github.com/inkyblackness/shocked-model"
+)
+
+// objectTableColumn is one column of the CSV table of an object class.
+type objectTableColumn struct {
+	name     string
+	readOnly bool
+	get      func(objID res.ObjectID, properties *model.GameObjectProperties) string
+	set      func(properties *model.GameObjectProperties, value string) error
+}
+
+// objectTableRow is a parsed row of an object class table.
+type objectTableRow struct {
+	number     int
+	objID      res.ObjectID
+	properties model.GameObjectProperties
+}
+
+// objectTableColumns returns the columns for the table of given class. The known data fields
+// come first, followed by one column for each byte not covered by a known field.
+func objectTableColumns(class int, properties map[res.ObjectID]*model.GameObjectProperties) []objectTableColumn {
+	columns := []objectTableColumn{
+		{name: "subclass", readOnly: true, get: func(objID res.ObjectID, properties *model.GameObjectProperties) string {
+			return fmt.Sprintf("%d", objID.Subclass)
+		}},
+		{name: "type", readOnly: true, get: func(objID res.ObjectID, properties *model.GameObjectProperties) string {
+			return fmt.Sprintf("%d", objID.Type)
+		}},
+		{name: "name", readOnly: true, get: func(objID res.ObjectID, properties *model.GameObjectProperties) string {
+			if properties.LongName[0] != nil {
+				return *properties.LongName[0]
+			}
+			return ""
+		}}}
+	fields := objectFields(class)
+	covered := map[objectDataBlock]map[int]bool{commonBlock: {}, genericBlock: {}, specificBlock: {}}
+
+	for _, field := range fields {
+		columns = append(columns, fieldTableColumn(field))
+		for offset := field.offset; offset < field.offset+field.size; offset++ {
+			covered[field.block][offset] = true
+		}
+	}
+	for _, block := range []objectDataBlock{commonBlock, genericBlock, specificBlock} {
+		blockField := objectDataField{block: block}
+		length := 0
+		for _, entry := range properties {
+			if blockLength := len(blockField.blockData(&entry.Data)); blockLength > length {
+				length = blockLength
+			}
+		}
+		for offset := 0; offset < length; offset++ {
+			if !covered[block][offset] {
+				field := objectDataField{name: fmt.Sprintf("%s.%d", block, offset), block: block, offset: offset, size: 1, maximum: 0xFF}
+				columns = append(columns, fieldTableColumn(field))
+			}
+		}
+	}
+
+	return columns
+}
+
+func fieldTableColumn(field objectDataField) objectTableColumn {
+	return objectTableColumn{
+		name: field.name,
+		get: func(objID res.ObjectID, properties *model.GameObjectProperties) string {
+			if value, ok := field.value(&properties.Data); ok {
+				return fmt.Sprintf("%d", value)
+			}
+			return ""
+		},
+		set: func(properties *model.GameObjectProperties, text string) error {
+			if _, ok := field.value(&properties.Data); !ok {
+				if text == "" {
+					return nil
+				}
+				return fmt.Errorf("Field is not available for this type")
+			}
+			value, err := strconv.ParseInt(text, 10, 64)
+			if err != nil {
+				return fmt.Errorf("Not a number: %q", text)
+			}
+			if err := field.schema().validateInteger(field.name, value); err != nil {
+				return err
+			}
+			field.setValue(&properties.Data, value)
+			return nil
+		}}
+}
+
+// writeObjectTable writes the properties of all given object types as CSV.
+func writeObjectTable(writer io.Writer, class int, objIDs []res.ObjectID, properties map[res.ObjectID]*model.GameObjectProperties) error {
+	columns := objectTableColumns(class, properties)
+	csvWriter := csv.NewWriter(writer)
+	record := make([]string, len(columns))
+
+	for index, column := range columns {
+		record[index] = column.name
+	}
+	csvWriter.Write(record)
+	for _, objID := range objIDs {
+		for index, column := range columns {
+			record[index] = column.get(objID, properties[objID])
+		}
+		csvWriter.Write(record)
+	}
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// readObjectTable parses a CSV table of given class. The current properties serve as base for the
+// resulting rows; they are not modified. All found problems are returned as table errors.
+func readObjectTable(reader io.Reader, class int, properties map[res.ObjectID]*model.GameObjectProperties) (rows []objectTableRow, tableErrors []TableError) {
+	columns := objectTableColumns(class, properties)
+	columnsByName := map[string]objectTableColumn{}
+	csvReader := csv.NewReader(reader)
+	records, err := csvReader.ReadAll()
+
+	if err != nil {
+		return nil, []TableError{{Message: err.Error()}}
+	}
+	if len(records) == 0 {
+		return nil, []TableError{{Message: "Table is empty"}}
+	}
+	for _, column := range columns {
+		columnsByName[column.name] = column
+	}
+	header := records[0]
+	subclassIndex, typeIndex := -1, -1
+	headerColumns := map[string]bool{}
+	for index, name := range header {
+		if _, known := columnsByName[name]; !known {
+			tableErrors = append(tableErrors, TableError{Row: 1, Column: name, Message: "Unknown column"})
+		} else if headerColumns[name] {
+			tableErrors = append(tableErrors, TableError{Row: 1, Column: name, Message: "Column is given more than once"})
+		}
+		headerColumns[name] = true
+		switch name {
+		case "subclass":
+			subclassIndex = index
+		case "type":
+			typeIndex = index
+		}
+	}
+	if (subclassIndex < 0) || (typeIndex < 0) {
+		tableErrors = append(tableErrors, TableError{Row: 1, Message: "Columns subclass and type are required"})
+	}
+	if len(tableErrors) > 0 {
+		return nil, tableErrors
+	}
+
+	seen := map[res.ObjectID]int{}
+	for recordIndex, record := range records[1:] {
+		rowNumber := recordIndex + 2
+		subclass, subclassErr := strconv.ParseInt(record[subclassIndex], 10, 8)
+		objType, typeErr := strconv.ParseInt(record[typeIndex], 10, 8)
+		objID := res.MakeObjectID(res.ObjectClass(class), res.ObjectSubclass(subclass), res.ObjectType(objType))
+
+		if (subclassErr != nil) || (typeErr != nil) || !isValidObjectID(objID) {
+			tableErrors = append(tableErrors, TableError{Row: rowNumber, Message: "Unknown object type"})
+			continue
+		}
+		if previous, duplicate := seen[objID]; duplicate {
+			tableErrors = append(tableErrors, TableError{Row: rowNumber, Message: fmt.Sprintf("Object type already given in row %d", previous)})
+			continue
+		}
+		seen[objID] = rowNumber
+
+		row := objectTableRow{number: rowNumber, objID: objID, properties: copyObjectProperties(properties[objID])}
+		for index, name := range header {
+			column := columnsByName[name]
+			if column.readOnly {
+				continue
+			}
+			if err := column.set(&row.properties, record[index]); err != nil {
+				tableErrors = append(tableErrors, TableError{Row: rowNumber, Column: name, Message: err.Error()})
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return
+}
+
+// copyObjectProperties returns a copy of the properties with separate data blocks.
+func copyObjectProperties(properties *model.GameObjectProperties) model.GameObjectProperties {
+	result := *properties
+
+	result.Data.Common = append([]byte(nil), properties.Data.Common...)
+	result.Data.Generic = append([]byte(nil), properties.Data.Generic...)
+	result.Data.Specific = append([]byte(nil), properties.Data.Specific...)
+
+	return result
+}
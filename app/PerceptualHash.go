@@ -0,0 +1,144 @@
+package app
+
+import (
+	"image"
+	"math/bits"
+)
+
+// perceptualHash is a 64-bit difference hash of an image. Images that look alike
+// have hashes with a small hamming distance.
+type perceptualHash uint64
+
+const (
+	hashWidth  = 9
+	hashHeight = 8
+)
+
+// hashImage computes the difference hash of given image. The image is reduced to
+// a grey 9x8 thumbnail, and each bit of the hash states whether a pixel is brighter
+// than its right neighbour.
+func hashImage(img image.Image) perceptualHash {
+	var thumb [hashHeight][hashWidth]float64
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+
+	for y := 0; y < hashHeight; y++ {
+		top := bounds.Min.Y + y*height/hashHeight
+		bottom := bounds.Min.Y + maxInt((y+1)*height/hashHeight, y*height/hashHeight+1)
+		for x := 0; x < hashWidth; x++ {
+			left := bounds.Min.X + x*width/hashWidth
+			right := bounds.Min.X + maxInt((x+1)*width/hashWidth, x*width/hashWidth+1)
+			thumb[y][x] = averageLuminance(img, image.Rect(left, top, right, bottom).Intersect(bounds))
+		}
+	}
+
+	var hash perceptualHash
+	for y := 0; y < hashHeight; y++ {
+		for x := 0; x < hashWidth-1; x++ {
+			hash <<= 1
+			if thumb[y][x] > thumb[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
+
+func averageLuminance(img image.Image, area image.Rectangle) float64 {
+	sum := 0.0
+	count := 0
+
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			count++
+		}
+	}
+	if count > 0 {
+		sum /= float64(count)
+	}
+
+	return sum
+}
+
+// distance returns the number of differing bits of two hashes.
+func (hash perceptualHash) distance(other perceptualHash) int {
+	return bits.OnesCount64(uint64(hash ^ other))
+}
+
+// similarity returns a score between 0.0 (completely different) and 1.0 (same hash).
+func (hash perceptualHash) similarity(other perceptualHash) float64 {
+	return 1.0 - float64(hash.distance(other))/64.0
+}
+
+// clusterHashes groups the indices of all hashes that are at most threshold bits apart,
+// directly or through other members of the group. Only groups with more than one member
+// are returned, each sorted by index.
+func clusterHashes(hashes []perceptualHash, threshold int) [][]int {
+	parents := make([]int, len(hashes))
+	for index := range parents {
+		parents[index] = index
+	}
+	var root func(int) int
+	root = func(index int) int {
+		for parents[index] != index {
+			parents[index] = parents[parents[index]]
+			index = parents[index]
+		}
+		return index
+	}
+
+	for a := 0; a < len(hashes); a++ {
+		for b := a + 1; b < len(hashes); b++ {
+			if hashes[a].distance(hashes[b]) <= threshold {
+				rootA, rootB := root(a), root(b)
+				if rootA < rootB {
+					parents[rootB] = rootA
+				} else {
+					parents[rootA] = rootB
+				}
+			}
+		}
+	}
+
+	groups := map[int][]int{}
+	var roots []int
+	for index := range hashes {
+		groupRoot := root(index)
+		if _, existing := groups[groupRoot]; !existing {
+			roots = append(roots, groupRoot)
+		}
+		groups[groupRoot] = append(groups[groupRoot], index)
+	}
+
+	var clusters [][]int
+	for _, groupRoot := range roots {
+		if len(groups[groupRoot]) > 1 {
+			clusters = append(clusters, groups[groupRoot])
+		}
+	}
+
+	return clusters
+}
+
+// nearestMembers returns, for each member of a cluster, the member whose hash is closest to its
+// own. Of equally close members, the first one is taken. Members are indices into hashes.
+func nearestMembers(hashes []perceptualHash, members []int) []int {
+	nearest := make([]int, len(members))
+
+	for index, member := range members {
+		best, bestDistance := -1, 0
+		for _, other := range members {
+			distance := hashes[member].distance(hashes[other])
+			if (other != member) && ((best < 0) || (distance < bestDistance)) {
+				best, bestDistance = other, distance
+			}
+		}
+		nearest[index] = best
+	}
+
+	return nearest
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// hashTestImage creates a grey image of given size with each pixel set by the given function.
+func hashTestImage(width, height int, grey func(x, y int) uint8) image.Image {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{Y: grey(x, y)})
+		}
+	}
+	return img
+}
+
+func TestHashImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		img      image.Image
+		expected perceptualHash
+	}{
+		{"uniform", hashTestImage(64, 64, func(x, y int) uint8 { return 128 }), 0},
+		{"brightening to the right", hashTestImage(90, 80, func(x, y int) uint8 { return uint8(x * 2) }), 0},
+		{"darkening to the right", hashTestImage(90, 80, func(x, y int) uint8 { return uint8(255 - x*2) }), 0xFFFFFFFFFFFFFFFF},
+		{"dark lower half", hashTestImage(90, 80, func(x, y int) uint8 {
+			if y < 40 {
+				return uint8(255 - x*2)
+			}
+			return 0
+		}), 0xFFFFFFFF00000000},
+		{"smaller than thumbnail", hashTestImage(3, 2, func(x, y int) uint8 { return uint8(255 - x*100) }), 0x2424242424242424},
+	}
+
+	for _, tc := range tests {
+		if result := hashImage(tc.img); result != tc.expected {
+			t.Errorf("%s: hash should be %016X, was %016X", tc.name, uint64(tc.expected), uint64(result))
+		}
+	}
+}
+
+func TestHashImageIgnoresBoundsOffset(t *testing.T) {
+	grey := func(x, y int) uint8 { return uint8((x * y) % 256) }
+	img := hashTestImage(72, 64, grey)
+	offset := image.NewGray(image.Rect(100, 200, 172, 264))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 72; x++ {
+			offset.SetGray(100+x, 200+y, color.Gray{Y: grey(x, y)})
+		}
+	}
+
+	if expected, result := hashImage(img), hashImage(offset); result != expected {
+		t.Errorf("Hash should be %016X, was %016X", uint64(expected), uint64(result))
+	}
+}
+
+func TestPerceptualHashSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b       perceptualHash
+		distance   int
+		similarity float64
+	}{
+		{0, 0, 0, 1.0},
+		{0, 1, 1, 1.0 - 1.0/64.0},
+		{0x00FF, 0xFF00, 16, 0.75},
+		{0, 0xFFFFFFFFFFFFFFFF, 64, 0.0},
+	}
+
+	for _, tc := range tests {
+		if result := tc.a.distance(tc.b); result != tc.distance {
+			t.Errorf("Distance of %X and %X should be %d, was %d", uint64(tc.a), uint64(tc.b), tc.distance, result)
+		}
+		if result := tc.a.similarity(tc.b); result != tc.similarity {
+			t.Errorf("Similarity of %X and %X should be %v, was %v", uint64(tc.a), uint64(tc.b), tc.similarity, result)
+		}
+	}
+}
+
+func TestClusterHashes(t *testing.T) {
+	tests := []struct {
+		name      string
+		hashes    []perceptualHash
+		threshold int
+		expected  [][]int
+	}{
+		{"empty", nil, 6, nil},
+		{"all distinct", []perceptualHash{0x00, 0xFF, 0xFF00}, 0, nil},
+		{"identical pair", []perceptualHash{0x0F, 0xF0, 0x0F}, 0, [][]int{{0, 2}}},
+		{"within threshold", []perceptualHash{0x00, 0x07, 0xFF}, 3, [][]int{{0, 1}}},
+		{"beyond threshold", []perceptualHash{0x00, 0x0F}, 3, nil},
+		{"chained", []perceptualHash{0x00, 0x03, 0x0F}, 2, [][]int{{0, 1, 2}}},
+		{"two groups", []perceptualHash{0xFF00, 0x00, 0xFF01, 0x01}, 1, [][]int{{0, 2}, {1, 3}}},
+		{"joined late", []perceptualHash{0x0F, 0xF0, 0x00}, 4, [][]int{{0, 1, 2}}},
+		{"full threshold", []perceptualHash{0x00, 0xFFFFFFFFFFFFFFFF}, 64, [][]int{{0, 1}}},
+	}
+
+	for _, tc := range tests {
+		if result := clusterHashes(tc.hashes, tc.threshold); !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("%s: clusters should be %v, were %v", tc.name, tc.expected, result)
+		}
+	}
+}
+
+func TestNearestMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		hashes   []perceptualHash
+		members  []int
+		expected []int
+	}{
+		{"pair", []perceptualHash{0x00, 0x01}, []int{0, 1}, []int{1, 0}},
+		{"chain", []perceptualHash{0x00, 0x03, 0x0F}, []int{0, 1, 2}, []int{1, 0, 1}},
+		{"subset of hashes", []perceptualHash{0x00, 0xFF, 0x01, 0xFE}, []int{1, 3}, []int{3, 1}},
+		{"first of equally close", []perceptualHash{0x01, 0x00, 0x03}, []int{0, 1, 2}, []int{1, 0, 0}},
+	}
+
+	for _, tc := range tests {
+		if result := nearestMembers(tc.hashes, tc.members); !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("%s: nearest members should be %v, were %v", tc.name, tc.expected, result)
+		}
+	}
+}
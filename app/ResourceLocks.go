@@ -0,0 +1,83 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// resourceLocks serialises read-modify-write sequences on the same resource, such as the
+// tiles and objects of a level, the properties of a texture or the types of an object class.
+// Without them, two concurrent partial updates could each write back the fields the other
+// one changed.
+type resourceLocks struct {
+	mutex sync.Mutex
+	locks map[string]*resourceLock
+}
+
+// resourceLock is the lock of one resource. users counts those holding or waiting for it;
+// the lock is forgotten once there are none, so that only locks in use are kept.
+type resourceLock struct {
+	sync.Mutex
+	users int
+}
+
+func newResourceLocks() *resourceLocks {
+	return &resourceLocks{locks: make(map[string]*resourceLock)}
+}
+
+func levelLockKey(projectID string, levelID int) string {
+	return fmt.Sprintf("%s/levels/%d", projectID, levelID)
+}
+
+func textureLockKey(projectID string, textureID int) string {
+	return fmt.Sprintf("%s/textures/%d", projectID, textureID)
+}
+
+func objectClassLockKey(projectID string, class int) string {
+	return fmt.Sprintf("%s/objects/%d", projectID, class)
+}
+
+// lock locks the resource with given key and returns the function to unlock it again.
+func (locks *resourceLocks) lock(key string) (unlock func()) {
+	locks.mutex.Lock()
+	lock, existing := locks.locks[key]
+	if !existing {
+		lock = new(resourceLock)
+		locks.locks[key] = lock
+	}
+	lock.users++
+	locks.mutex.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		locks.mutex.Lock()
+		lock.users--
+		if lock.users == 0 {
+			delete(locks.locks, key)
+		}
+		locks.mutex.Unlock()
+	}
+}
+
+// lockAll locks the resources with given keys and returns the function to unlock them again.
+// The resources are locked in the order of their keys, so that two requests locking the same
+// resources can not wait for each other. Keys given more than once are locked once.
+func (locks *resourceLocks) lockAll(keys ...string) (unlock func()) {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	var unlocks []func()
+
+	for index, key := range sorted {
+		if (index == 0) || (key != sorted[index-1]) {
+			unlocks = append(unlocks, locks.lock(key))
+		}
+	}
+
+	return func() {
+		for index := len(unlocks) - 1; index >= 0; index-- {
+			unlocks[index]()
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestResourceLocksForgetUnusedLocks(t *testing.T) {
+	locks := newResourceLocks()
+
+	unlockA := locks.lock("a")
+	unlockB := locks.lock("b")
+	if count := len(locks.locks); count != 2 {
+		t.Errorf("Two locks should be kept while held, were %d", count)
+	}
+	unlockA()
+	unlockB()
+	if count := len(locks.locks); count != 0 {
+		t.Errorf("No locks should be kept once released, were %d", count)
+	}
+}
+
+func TestResourceLocksSerialiseSameKey(t *testing.T) {
+	locks := newResourceLocks()
+	var group sync.WaitGroup
+	counter := 0
+
+	for worker := 0; worker < 8; worker++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			for step := 0; step < 1000; step++ {
+				unlock := locks.lock("level")
+				value := counter
+				counter = value + 1
+				unlock()
+			}
+		}()
+	}
+	group.Wait()
+
+	if counter != 8000 {
+		t.Errorf("All increments should be kept, counter is %d", counter)
+	}
+	if count := len(locks.locks); count != 0 {
+		t.Errorf("No locks should be kept once released, were %d", count)
+	}
+}
+
+func TestResourceLocksLockAllInAnyOrder(t *testing.T) {
+	locks := newResourceLocks()
+	var group sync.WaitGroup
+
+	for worker := 0; worker < 8; worker++ {
+		keys := []string{"a", "b"}
+		if worker%2 == 1 {
+			keys = []string{"b", "a"}
+		}
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			for step := 0; step < 1000; step++ {
+				locks.lockAll(keys...)()
+			}
+		}()
+	}
+	group.Wait()
+
+	unlock := locks.lockAll("a", "a")
+	if count := len(locks.locks); count != 1 {
+		t.Errorf("A key given twice should be locked once, locks were %d", count)
+	}
+	unlock()
+	if count := len(locks.locks); count != 0 {
+		t.Errorf("No locks should be kept once released, were %d", count)
+	}
+}
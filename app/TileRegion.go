@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/emicklei/go-restful"
+)
+
+// tileAreaFromRequest reads the tile area of the query parameters x0, y0, x1 and y1. given is false if
+// none of them is present. The returned area is ordered (x0 <= x1, y0 <= y1) and within the map.
+func tileAreaFromRequest(request *restful.Request) (area TileArea, given bool, err error) {
+	names := []string{"x0", "y0", "x1", "y1"}
+	values := make([]int, len(names))
+	present := 0
+
+	for index, name := range names {
+		if text := request.QueryParameter(name); text != "" {
+			values[index], err = strconv.Atoi(text)
+			if err != nil {
+				return area, true, fmt.Errorf("Parameter %s is not a number", name)
+			}
+			if (values[index] < 0) || (values[index] >= levelTileCount) {
+				return area, true, fmt.Errorf("Parameter %s must be between 0 and %d", name, levelTileCount-1)
+			}
+			present++
+		}
+	}
+	if present == 0 {
+		return TileArea{X0: 0, Y0: 0, X1: levelTileCount - 1, Y1: levelTileCount - 1}, false, nil
+	}
+	if present != len(names) {
+		return area, true, fmt.Errorf("Area requires all of x0, y0, x1 and y1")
+	}
+	area = TileArea{
+		X0: minInt(values[0], values[2]), Y0: minInt(values[1], values[3]),
+		X1: maxInt(values[0], values[2]), Y1: maxInt(values[1], values[3])}
+
+	return area, true, nil
+}
+
+// isWithinMap returns true if the ordered area is completely within the map.
+func isWithinMap(area TileArea) bool {
+	return (area.X0 >= 0) && (area.Y0 >= 0) && (area.X1 < levelTileCount) && (area.Y1 < levelTileCount) &&
+		(area.X0 <= area.X1) && (area.Y0 <= area.Y1)
+}
+
+// levelTextureMapping returns a function that maps texture indices of the source level to the indices
+// of the same textures in the target level. The texture lists contain the global texture IDs.
+func levelTextureMapping(source, target []int) func(index int) (int, bool) {
+	targetIndices := map[int]int{}
+	for index := len(target) - 1; index >= 0; index-- {
+		targetIndices[target[index]] = index
+	}
+
+	return func(index int) (int, bool) {
+		if (index < 0) || (index >= len(source)) {
+			return index, false
+		}
+		targetIndex, available := targetIndices[source[index]]
+		return targetIndex, available
+	}
+}
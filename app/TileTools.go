@@ -0,0 +1,107 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+
+	model "github.com/inkyblackness/shocked-model"
+)
+
+// tileAccess is the part of a level needed by the tile tools.
+type tileAccess interface {
+	TileProperties(x, y int) model.TileProperties
+	SetTileProperties(x, y int, properties model.TileProperties)
+}
+
+// sameTileBoundary returns a function telling whether a tile belongs to the same area as the
+// start tile, according to the boundary ("type" or "height").
+func sameTileBoundary(level tileAccess, startX, startY int, boundedBy string) (func(x, y int) bool, error) {
+	start := level.TileProperties(startX, startY)
+
+	switch boundedBy {
+	case "", "type":
+		return func(x, y int) bool {
+			return reflect.DeepEqual(level.TileProperties(x, y).Type, start.Type)
+		}, nil
+	case "height":
+		return func(x, y int) bool {
+			return reflect.DeepEqual(level.TileProperties(x, y).FloorHeight, start.FloorHeight)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("Unknown boundary %q", boundedBy)
+}
+
+// floodFillArea returns the coordinates of all tiles connected to the start tile through
+// horizontal and vertical neighbours that belong to the same area.
+func floodFillArea(startX, startY int, sameArea func(x, y int) bool) (tiles []TileCoordinate) {
+	var visited [levelTileCount][levelTileCount]bool
+	pending := []TileCoordinate{{X: startX, Y: startY}}
+
+	visited[startY][startX] = true
+	for len(pending) > 0 {
+		tile := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		tiles = append(tiles, tile)
+
+		for _, offset := range []TileCoordinate{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}} {
+			x, y := tile.X+offset.X, tile.Y+offset.Y
+			if (x >= 0) && (x < levelTileCount) && (y >= 0) && (y < levelTileCount) && !visited[y][x] && sameArea(x, y) {
+				visited[y][x] = true
+				pending = append(pending, TileCoordinate{X: x, Y: y})
+			}
+		}
+	}
+
+	return
+}
+
+// matchingTiles returns the coordinates of all tiles whose properties match the pattern.
+// The pattern must be a JSON object.
+func matchingTiles(level tileAccess, pattern interface{}) (tiles []TileCoordinate, err error) {
+	if _, isObject := pattern.(map[string]interface{}); !isObject {
+		return nil, fmt.Errorf("Match must be a JSON object")
+	}
+	for y := 0; y < levelTileCount; y++ {
+		for x := 0; x < levelTileCount; x++ {
+			var value interface{}
+			value, err = jsonValue(level.TileProperties(x, y))
+			if err != nil {
+				return
+			}
+			if matchesPattern(value, pattern) {
+				tiles = append(tiles, TileCoordinate{X: x, Y: y})
+			}
+		}
+	}
+
+	return
+}
+
+// patchTiles applies the merge patch to all given tiles and returns those that were changed.
+// The patch must be a JSON object. All patches are prepared before any tile is modified; whether
+// a tile changed is determined from the tile as it is stored after the write.
+func patchTiles(level tileAccess, tiles []TileCoordinate, patch interface{}) (changed []TileCoordinate, err error) {
+	if _, isObject := patch.(map[string]interface{}); !isObject {
+		return nil, fmt.Errorf("Properties must be a JSON object")
+	}
+	patched := make([]model.TileProperties, len(tiles))
+	changed = []TileCoordinate{}
+
+	for index, tile := range tiles {
+		err = applyMergePatch(level.TileProperties(tile.X, tile.Y), patch, &patched[index])
+		if err != nil {
+			return nil, err
+		}
+	}
+	for index, tile := range tiles {
+		before, _ := jsonValue(level.TileProperties(tile.X, tile.Y))
+		level.SetTileProperties(tile.X, tile.Y, patched[index])
+		after, _ := jsonValue(level.TileProperties(tile.X, tile.Y))
+		if !reflect.DeepEqual(before, after) {
+			changed = append(changed, tile)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	model "github.com/inkyblackness/shocked-model"
+)
+
+// tileTransform maps tile coordinates and directions of a copied area of tiles.
+// North is towards increasing Y.
+type tileTransform struct {
+	width, height int
+	mirror        string
+	quarterTurns  int
+}
+
+func newTileTransform(area TileArea, rotation int, mirror string) (transform tileTransform, err error) {
+	if (rotation%90 != 0) || (rotation < 0) || (rotation >= 360) {
+		return transform, fmt.Errorf("Rotation must be 0, 90, 180 or 270")
+	}
+	if (mirror != "") && (mirror != "horizontal") && (mirror != "vertical") {
+		return transform, fmt.Errorf("Mirror must be horizontal or vertical")
+	}
+	transform.width = area.X1 - area.X0 + 1
+	transform.height = area.Y1 - area.Y0 + 1
+	transform.mirror = mirror
+	transform.quarterTurns = rotation / 90
+
+	return
+}
+
+// size returns the dimensions of the transformed area.
+func (transform tileTransform) size() (width, height int) {
+	if transform.quarterTurns%2 == 1 {
+		return transform.height, transform.width
+	}
+	return transform.width, transform.height
+}
+
+// position maps a position relative to the source area to one relative to the target area.
+func (transform tileTransform) position(x, y int) (int, int) {
+	width, height := transform.width, transform.height
+
+	switch transform.mirror {
+	case "horizontal":
+		x = width - 1 - x
+	case "vertical":
+		y = height - 1 - y
+	}
+	for turn := 0; turn < transform.quarterTurns; turn++ {
+		x, y = y, width-1-x
+		width, height = height, width
+	}
+
+	return x, y
+}
+
+var clockwiseDirections = map[string]string{"North": "East", "East": "South", "South": "West", "West": "North"}
+
+// direction maps a compass direction.
+func (transform tileTransform) direction(direction string) string {
+	switch {
+	case (transform.mirror == "horizontal") && (direction == "East"):
+		direction = "West"
+	case (transform.mirror == "horizontal") && (direction == "West"):
+		direction = "East"
+	case (transform.mirror == "vertical") && (direction == "North"):
+		direction = "South"
+	case (transform.mirror == "vertical") && (direction == "South"):
+		direction = "North"
+	}
+	for turn := 0; turn < transform.quarterTurns; turn++ {
+		direction = clockwiseDirections[direction]
+	}
+
+	return direction
+}
+
+// tileTypePrefixes are the shapes of tile types that carry directions in their names.
+var tileTypePrefixes = []string{"diagonalOpen", "slope", "valley", "ridge"}
+
+// tileType maps a tile type. Directional types are named by their shape followed by one or two
+// direction groups, separated by "To". A group names a side (North) or a corner (SouthEast),
+// corners starting with North or South.
+func (transform tileTransform) tileType(tileType model.TileType) model.TileType {
+	name := string(tileType)
+
+	for _, prefix := range tileTypePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			groups := strings.Split(name[len(prefix):], "To")
+			for index, group := range groups {
+				groups[index] = transform.directionGroup(group)
+			}
+			return model.TileType(prefix + strings.Join(groups, "To"))
+		}
+	}
+
+	return tileType
+}
+
+func (transform tileTransform) directionGroup(group string) string {
+	var directions []string
+
+	for len(group) > 0 {
+		found := false
+		for direction := range clockwiseDirections {
+			if strings.HasPrefix(group, direction) {
+				directions = append(directions, transform.direction(direction))
+				group = group[len(direction):]
+				found = true
+			}
+		}
+		if !found {
+			return group
+		}
+	}
+	if (len(directions) == 2) && ((directions[1] == "North") || (directions[1] == "South")) {
+		directions[0], directions[1] = directions[1], directions[0]
+	}
+
+	return strings.Join(directions, "")
+}
+
+// textureRotations maps the number of quarter turns of a floor or ceiling texture.
+// Mirroring can not be expressed by texture rotations; see tileProperties.
+func (transform tileTransform) textureRotations(rotations int) int {
+	return (rotations + transform.quarterTurns) % 4
+}
+
+// tileProperties returns the transformed properties of a tile. Texture indices are mapped with
+// the given function, which returns false for textures that are not available.
+// A mirrored copy of an open tile with floor or ceiling texture is refused, as the texture
+// would keep its orientation and not match the mirrored tile.
+func (transform tileTransform) tileProperties(properties model.TileProperties,
+	texture func(index int) (int, bool)) (result model.TileProperties, err error) {
+	result = properties
+
+	if (transform.mirror != "") && hasVisibleFlatTexture(properties) {
+		return result, fmt.Errorf("Tiles with floor or ceiling texture can not be mirrored")
+	}
+	if properties.Type != nil {
+		tileType := transform.tileType(*properties.Type)
+		result.Type = &tileType
+	}
+	if properties.RealWorld != nil {
+		realWorld := *properties.RealWorld
+		for _, entry := range []**int{&realWorld.FloorTexture, &realWorld.CeilingTexture, &realWorld.WallTexture} {
+			if *entry != nil {
+				index, available := texture(**entry)
+				if !available {
+					return result, fmt.Errorf("Texture %d is not available in the target level", **entry)
+				}
+				*entry = &index
+			}
+		}
+		for _, entry := range []**int{&realWorld.FloorTextureRotations, &realWorld.CeilingTextureRotations} {
+			if *entry != nil {
+				rotations := transform.textureRotations(**entry)
+				*entry = &rotations
+			}
+		}
+		result.RealWorld = &realWorld
+	}
+
+	return
+}
+
+// hasVisibleFlatTexture returns true for tiles that are not solid and have a floor or ceiling texture.
+func hasVisibleFlatTexture(properties model.TileProperties) bool {
+	if (properties.RealWorld == nil) || ((properties.Type != nil) && (*properties.Type == solidTileType)) {
+		return false
+	}
+
+	return (properties.RealWorld.FloorTexture != nil) || (properties.RealWorld.CeilingTexture != nil)
+}
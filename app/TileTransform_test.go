@@ -0,0 +1,146 @@
+package app
+
+import (
+	"testing"
+
+	model "github.com/inkyblackness/shocked-model"
+)
+
+// transformTestArea is three tiles wide and two tiles high, so that mixing up width and height shows.
+var transformTestArea = TileArea{X0: 10, Y0: 20, X1: 12, Y1: 21}
+
+var transformTestPositions = [][2]int{{0, 0}, {2, 0}, {1, 1}}
+
+var transformTestDirections = []string{"North", "East", "South", "West"}
+
+var transformTestTypes = []model.TileType{"open", "diagonalOpenSouthEast", "slopeSouthToNorth",
+	"valleySouthEastToNorthWest", "ridgeNorthWestToSouthEast"}
+
+var transformTestCases = []struct {
+	rotation   int
+	mirror     string
+	positions  [][2]int
+	directions []string
+	types      []model.TileType
+}{
+	{
+		rotation: 0, mirror: "",
+		positions:  [][2]int{{0, 0}, {2, 0}, {1, 1}},
+		directions: []string{"North", "East", "South", "West"},
+		types:      []model.TileType{"open", "diagonalOpenSouthEast", "slopeSouthToNorth", "valleySouthEastToNorthWest", "ridgeNorthWestToSouthEast"}},
+	{
+		rotation: 90, mirror: "",
+		positions:  [][2]int{{0, 2}, {0, 0}, {1, 1}},
+		directions: []string{"East", "South", "West", "North"},
+		types:      []model.TileType{"open", "diagonalOpenSouthWest", "slopeWestToEast", "valleySouthWestToNorthEast", "ridgeNorthEastToSouthWest"}},
+	{
+		rotation: 180, mirror: "",
+		positions:  [][2]int{{2, 1}, {0, 1}, {1, 0}},
+		directions: []string{"South", "West", "North", "East"},
+		types:      []model.TileType{"open", "diagonalOpenNorthWest", "slopeNorthToSouth", "valleyNorthWestToSouthEast", "ridgeSouthEastToNorthWest"}},
+	{
+		rotation: 270, mirror: "",
+		positions:  [][2]int{{1, 0}, {1, 2}, {0, 1}},
+		directions: []string{"West", "North", "East", "South"},
+		types:      []model.TileType{"open", "diagonalOpenNorthEast", "slopeEastToWest", "valleyNorthEastToSouthWest", "ridgeSouthWestToNorthEast"}},
+	{
+		rotation: 0, mirror: "horizontal",
+		positions:  [][2]int{{2, 0}, {0, 0}, {1, 1}},
+		directions: []string{"North", "West", "South", "East"},
+		types:      []model.TileType{"open", "diagonalOpenSouthWest", "slopeSouthToNorth", "valleySouthWestToNorthEast", "ridgeNorthEastToSouthWest"}},
+	{
+		rotation: 90, mirror: "horizontal",
+		positions:  [][2]int{{0, 0}, {0, 2}, {1, 1}},
+		directions: []string{"East", "North", "West", "South"},
+		types:      []model.TileType{"open", "diagonalOpenNorthWest", "slopeWestToEast", "valleyNorthWestToSouthEast", "ridgeSouthEastToNorthWest"}},
+	{
+		rotation: 180, mirror: "horizontal",
+		positions:  [][2]int{{0, 1}, {2, 1}, {1, 0}},
+		directions: []string{"South", "East", "North", "West"},
+		types:      []model.TileType{"open", "diagonalOpenNorthEast", "slopeNorthToSouth", "valleyNorthEastToSouthWest", "ridgeSouthWestToNorthEast"}},
+	{
+		rotation: 270, mirror: "horizontal",
+		positions:  [][2]int{{1, 2}, {1, 0}, {0, 1}},
+		directions: []string{"West", "South", "East", "North"},
+		types:      []model.TileType{"open", "diagonalOpenSouthEast", "slopeEastToWest", "valleySouthEastToNorthWest", "ridgeNorthWestToSouthEast"}},
+	{
+		rotation: 0, mirror: "vertical",
+		positions:  [][2]int{{0, 1}, {2, 1}, {1, 0}},
+		directions: []string{"South", "East", "North", "West"},
+		types:      []model.TileType{"open", "diagonalOpenNorthEast", "slopeNorthToSouth", "valleyNorthEastToSouthWest", "ridgeSouthWestToNorthEast"}},
+	{
+		rotation: 90, mirror: "vertical",
+		positions:  [][2]int{{1, 2}, {1, 0}, {0, 1}},
+		directions: []string{"West", "South", "East", "North"},
+		types:      []model.TileType{"open", "diagonalOpenSouthEast", "slopeEastToWest", "valleySouthEastToNorthWest", "ridgeNorthWestToSouthEast"}},
+	{
+		rotation: 180, mirror: "vertical",
+		positions:  [][2]int{{2, 0}, {0, 0}, {1, 1}},
+		directions: []string{"North", "West", "South", "East"},
+		types:      []model.TileType{"open", "diagonalOpenSouthWest", "slopeSouthToNorth", "valleySouthWestToNorthEast", "ridgeNorthEastToSouthWest"}},
+	{
+		rotation: 270, mirror: "vertical",
+		positions:  [][2]int{{0, 0}, {0, 2}, {1, 1}},
+		directions: []string{"East", "North", "West", "South"},
+		types:      []model.TileType{"open", "diagonalOpenNorthWest", "slopeWestToEast", "valleyNorthWestToSouthEast", "ridgeSouthEastToNorthWest"}},
+}
+
+func newTestTileTransform(t *testing.T, rotation int, mirror string) tileTransform {
+	transform, err := newTileTransform(transformTestArea, rotation, mirror)
+	if err != nil {
+		t.Fatalf("Unexpected error for rotation %d, mirror %q: %v", rotation, mirror, err)
+	}
+	return transform
+}
+
+func TestTileTransformPosition(t *testing.T) {
+	for _, tc := range transformTestCases {
+		transform := newTestTileTransform(t, tc.rotation, tc.mirror)
+		for index, source := range transformTestPositions {
+			x, y := transform.position(source[0], source[1])
+			if expected := tc.positions[index]; (x != expected[0]) || (y != expected[1]) {
+				t.Errorf("Rotation %d, mirror %q: position %v should map to %v, was %d/%d",
+					tc.rotation, tc.mirror, source, expected, x, y)
+			}
+		}
+	}
+}
+
+func TestTileTransformDirection(t *testing.T) {
+	for _, tc := range transformTestCases {
+		transform := newTestTileTransform(t, tc.rotation, tc.mirror)
+		for index, source := range transformTestDirections {
+			if result := transform.direction(source); result != tc.directions[index] {
+				t.Errorf("Rotation %d, mirror %q: direction %s should map to %s, was %s",
+					tc.rotation, tc.mirror, source, tc.directions[index], result)
+			}
+		}
+	}
+}
+
+func TestTileTransformTileType(t *testing.T) {
+	for _, tc := range transformTestCases {
+		transform := newTestTileTransform(t, tc.rotation, tc.mirror)
+		for index, source := range transformTestTypes {
+			if result := transform.tileType(source); result != tc.types[index] {
+				t.Errorf("Rotation %d, mirror %q: tile type %s should map to %s, was %s",
+					tc.rotation, tc.mirror, source, tc.types[index], result)
+			}
+		}
+	}
+}
+
+func TestTileTransformSizeSwapsForQuarterTurns(t *testing.T) {
+	for _, tc := range transformTestCases {
+		transform := newTestTileTransform(t, tc.rotation, tc.mirror)
+		width, height := transform.size()
+		expectedWidth, expectedHeight := 3, 2
+		if tc.rotation%180 != 0 {
+			expectedWidth, expectedHeight = 2, 3
+		}
+		if (width != expectedWidth) || (height != expectedHeight) {
+			t.Errorf("Rotation %d, mirror %q: size should be %dx%d, was %dx%d",
+				tc.rotation, tc.mirror, expectedWidth, expectedHeight, width, height)
+		}
+	}
+}
@@ -1,12 +1,17 @@
 package app
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 
+	goimage "image"
 	"image/color"
+	"image/gif"
 	"image/png"
 
 	"github.com/emicklei/go-restful"
@@ -19,13 +24,17 @@ import (
 
 // WorkspaceResource handles all requests for a workspace.
 type WorkspaceResource struct {
-	ws *core.Workspace
+	ws            *core.Workspace
+	objectIndices *levelObjectIndices
+	locks         *resourceLocks
 }
 
 // NewWorkspaceResource returns a new workspace resource instance.
 func NewWorkspaceResource(container *restful.Container, workspace *core.Workspace) *WorkspaceResource {
 	resource := &WorkspaceResource{
-		ws: workspace}
+		ws:            workspace,
+		objectIndices: newLevelObjectIndices(),
+		locks:         newResourceLocks()}
 
 	service1 := new(restful.WebService)
 
@@ -87,6 +96,15 @@ func NewWorkspaceResource(container *restful.Container, workspace *core.Workspac
 		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
 		Writes(model.Textures{}))
 
+	service2.Route(service2.GET("{project-id}/textures/duplicates").To(resource.getTextureDuplicates).
+		// docs
+		Doc("get clusters of textures that look alike").
+		Operation("getTextureDuplicates").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.QueryParameter("size", "Size of the compared texture images; Default: large").DataType("string")).
+		Param(service2.QueryParameter("threshold", "Maximum number of differing hash bits (0-64); Default: 6").DataType("int")).
+		Writes(TextureDuplicates{}))
+
 	service2.Route(service2.GET("{project-id}/textures/{texture-id}").To(resource.getTexture).
 		// docs
 		Doc("get texture").
@@ -104,6 +122,17 @@ func NewWorkspaceResource(container *restful.Container, workspace *core.Workspac
 		Reads(model.TextureProperties{}).
 		Writes(model.Texture{}))
 
+	service2.Route(service2.PATCH("{project-id}/textures/{texture-id}").To(resource.patchTexture).
+		// docs
+		Doc("modify texture with a JSON Merge Patch; "+
+			"Only a subset of RFC 7396 is supported: properties can not be removed, so a patch containing null is rejected with 400").
+		Operation("patchTexture").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("texture-id", "identifier of the texture").DataType("int")).
+		Consumes(mergePatchMimeType, restful.MIME_JSON).
+		Reads(model.TextureProperties{}).
+		Writes(model.Texture{}))
+
 	service2.Route(service2.GET("{project-id}/textures/{texture-id}/{texture-size}").To(resource.getTextureImage).
 		// docs
 		Doc("get texture image").
@@ -113,6 +142,16 @@ func NewWorkspaceResource(container *restful.Container, workspace *core.Workspac
 		Param(service2.PathParameter("texture-size", "Size of the texture").DataType("string")).
 		Writes(model.Image{}))
 
+	service2.Route(service2.PUT("{project-id}/textures/{texture-id}/{texture-size}").To(resource.setTextureImage).
+		// docs
+		Doc("set texture image properties").
+		Operation("setTextureImage").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("texture-id", "identifier of the texture").DataType("int")).
+		Param(service2.PathParameter("texture-size", "Size of the texture").DataType("string")).
+		Reads(model.ImageProperties{}).
+		Writes(model.Image{}))
+
 	service2.Route(service2.GET("{project-id}/textures/{texture-id}/{texture-size}/raw").To(resource.getTextureImageAsRaw).
 		// docs
 		Doc("get texture image as raw bitmap").
@@ -120,7 +159,8 @@ func NewWorkspaceResource(container *restful.Container, workspace *core.Workspac
 		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
 		Param(service2.PathParameter("texture-id", "identifier of the texture").DataType("int")).
 		Param(service2.PathParameter("texture-size", "Size of the texture").DataType("string")).
-		Writes(model.RawBitmap{}))
+		Writes(model.RawBitmap{}).
+		Produces(restful.MIME_JSON, restful.MIME_XML, restful.MIME_OCTET))
 
 	service2.Route(service2.GET("{project-id}/textures/{texture-id}/{texture-size}/png").To(resource.getTextureImageAsPng).
 		// docs
@@ -129,8 +169,57 @@ func NewWorkspaceResource(container *restful.Container, workspace *core.Workspac
 		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
 		Param(service2.PathParameter("texture-id", "identifier of the texture").DataType("int")).
 		Param(service2.PathParameter("texture-size", "Size of the texture").DataType("string")).
+		Param(service2.QueryParameter("transparent", "Whether palette index 0 is transparent; Default: false").DataType("boolean")).
+		Param(service2.QueryParameter("scale", "Nearest-neighbour upscaling factor; Default: 1").DataType("int")).
+		Param(service2.QueryParameter("palette", "identifier of the palette, \"game\" or a resource ID; Default: game").DataType("string")).
 		Produces("image/png"))
 
+	service2.Route(service2.GET("{project-id}/textures/{texture-id}/animation.gif").To(resource.getTextureAnimationAsGif).
+		// docs
+		Doc("get texture animation as GIF").
+		Operation("getTextureAnimationAsGif").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("texture-id", "identifier of the texture").DataType("int")).
+		Param(service2.QueryParameter("size", "Size of the texture frames; Default: large").DataType("string")).
+		Param(service2.QueryParameter("delay", "Time each frame is shown, in 100ths of a second (1-1000); "+
+			"Default: 25, which only approximates the rate of the game").DataType("int")).
+		Produces("image/gif"))
+
+	service2.Route(service2.GET("{project-id}/objects").To(resource.getGameObjectClasses).
+		// docs
+		Doc("get game object catalogue").
+		Operation("getGameObjectClasses").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Writes(GameObjectClasses{}))
+
+	service2.Route(service2.GET("{project-id}/objects/{class}").To(resource.getGameObjectClass).
+		// docs
+		Doc("get game object class; With a .csv suffix on the class, get the properties of all types as CSV").
+		Operation("getGameObjectClass").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class, optionally with .csv suffix").DataType("string")).
+		Produces(restful.MIME_JSON, restful.MIME_XML, csvMimeType).
+		Writes(GameObjectClass{}))
+
+	service2.Route(service2.PUT("{project-id}/objects/{class}").To(resource.setGameObjectClassTable).
+		// docs
+		Doc("set the properties of all types of a class from CSV").
+		Operation("setGameObjectClassTable").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class, with .csv suffix").DataType("string")).
+		Consumes(csvMimeType).
+		Produces(csvMimeType, restful.MIME_JSON).
+		Returns(http.StatusBadRequest, "table is invalid", TableErrors{}))
+
+	service2.Route(service2.GET("{project-id}/objects/{class}/{subclass}").To(resource.getGameObjectSubclass).
+		// docs
+		Doc("get game object subclass").
+		Operation("getGameObjectSubclass").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the subclass").DataType("int")).
+		Writes(GameObjectSubclass{}))
+
 	service2.Route(service2.GET("{project-id}/objects/{class}/{subclass}/{type}").To(resource.getGameObject).
 		// docs
 		Doc("get game object").
@@ -141,6 +230,84 @@ func NewWorkspaceResource(container *restful.Container, workspace *core.Workspac
 		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
 		Writes(model.GameObject{}))
 
+	service2.Route(service2.PUT("{project-id}/objects/{class}/{subclass}/{type}").To(resource.setGameObject).
+		// docs
+		Doc("set game object properties").
+		Operation("setGameObject").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Reads(model.GameObjectProperties{}).
+		Writes(model.GameObject{}))
+
+	service2.Route(service2.GET("{project-id}/objects/{class}/{subclass}/{type}/fields").To(resource.getGameObjectFields).
+		// docs
+		Doc("get the known data fields of a game object; Described by the object class schema").
+		Operation("getGameObjectFields").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Writes(GameObjectFields{}))
+
+	service2.Route(service2.PUT("{project-id}/objects/{class}/{subclass}/{type}/fields").To(resource.setGameObjectFields).
+		// docs
+		Doc("set the known data fields of a game object; Validated with the object class schema").
+		Operation("setGameObjectFields").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Reads(GameObjectFields{}).
+		Writes(GameObjectFields{}))
+
+	service2.Route(service2.GET("{project-id}/objects/{class}/{subclass}/{type}/icon").To(resource.getObjectIcon).
+		// docs
+		Doc("get object icon").
+		Operation("getObjectIcon").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Writes(model.Image{}))
+
+	service2.Route(service2.PUT("{project-id}/objects/{class}/{subclass}/{type}/icon").To(resource.setObjectIcon).
+		// docs
+		Doc("set object icon properties").
+		Operation("setObjectIcon").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Reads(model.ImageProperties{}).
+		Writes(model.Image{}))
+
+	service2.Route(service2.GET("{project-id}/objects/{class}/{subclass}/{type}/icon/png").To(resource.getObjectIconAsPng).
+		// docs
+		Doc("get object icon as PNG").
+		Operation("getObjectIconAsPng").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Param(service2.QueryParameter("transparent", "Whether palette index 0 is transparent; Default: false").DataType("boolean")).
+		Param(service2.QueryParameter("scale", "Nearest-neighbour upscaling factor; Default: 1").DataType("int")).
+		Param(service2.QueryParameter("palette", "identifier of the palette, \"game\" or a resource ID; Default: game").DataType("string")).
+		Produces("image/png"))
+
+	service2.Route(service2.PUT("{project-id}/objects/{class}/{subclass}/{type}/icon/png").To(resource.setObjectIconFromPng).
+		// docs
+		Doc("set object icon from PNG of at most 128x128 pixels").
+		Operation("setObjectIconFromPng").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Consumes("image/png").
+		Produces(restful.MIME_JSON, restful.MIME_XML).
+		Writes(model.Image{}))
+
 	service2.Route(service2.GET("{project-id}/objects/{class}/{subclass}/{type}/icon/raw").To(resource.getObjectIconAsRaw).
 		// docs
 		Doc("get object icon as raw bitmap").
@@ -149,7 +316,65 @@ func NewWorkspaceResource(container *restful.Container, workspace *core.Workspac
 		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
 		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
 		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
-		Writes(model.RawBitmap{}))
+		Writes(model.RawBitmap{}).
+		Produces(restful.MIME_JSON, restful.MIME_XML, restful.MIME_OCTET))
+
+	service2.Route(service2.GET("{project-id}/objects/{class}/{subclass}/{type}/bitmaps").To(resource.getObjectBitmaps).
+		// docs
+		Doc("get all bitmaps of an object").
+		Operation("getObjectBitmaps").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Writes(ObjectBitmaps{}))
+
+	service2.Route(service2.GET("{project-id}/objects/{class}/{subclass}/{type}/bitmaps/{frame}").To(resource.getObjectBitmap).
+		// docs
+		Doc("get object bitmap").
+		Operation("getObjectBitmap").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("frame", "index of the bitmap").DataType("int")).
+		Writes(model.Image{}))
+
+	service2.Route(service2.GET("{project-id}/objects/{class}/{subclass}/{type}/bitmaps/{frame}/raw").To(resource.getObjectBitmapAsRaw).
+		// docs
+		Doc("get object bitmap as raw bitmap").
+		Operation("getObjectBitmapAsRaw").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("frame", "index of the bitmap").DataType("int")).
+		Writes(model.RawBitmap{}).
+		Produces(restful.MIME_JSON, restful.MIME_XML, restful.MIME_OCTET))
+
+	service2.Route(service2.GET("{project-id}/objects/{class}/{subclass}/{type}/bitmaps/{frame}/png").To(resource.getObjectBitmapAsPng).
+		// docs
+		Doc("get object bitmap as PNG").
+		Operation("getObjectBitmapAsPng").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("frame", "index of the bitmap").DataType("int")).
+		Param(service2.QueryParameter("transparent", "Whether palette index 0 is transparent; Default: false").DataType("boolean")).
+		Param(service2.QueryParameter("scale", "Nearest-neighbour upscaling factor; Default: 1").DataType("int")).
+		Param(service2.QueryParameter("palette", "identifier of the palette, \"game\" or a resource ID; Default: game").DataType("string")).
+		Produces("image/png"))
+
+	service2.Route(service2.GET("{project-id}/objects/{class}/{subclass}/{type}/placements").To(resource.getObjectPlacements).
+		// docs
+		Doc("get all placements of an object in the levels").
+		Operation("getObjectPlacements").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("class", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("subclass", "identifier of the class").DataType("int")).
+		Param(service2.PathParameter("type", "identifier of the class").DataType("int")).
+		Writes(ObjectPlacements{}))
 
 	service2.Route(service2.GET("{project-id}/archive/levels").To(resource.getLevels).
 		// docs
@@ -166,6 +391,30 @@ func NewWorkspaceResource(container *restful.Container, workspace *core.Workspac
 		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
 		Writes(model.Level{}))
 
+	service2.Route(service2.PUT("{project-id}/archive/levels/{level-id}").To(resource.setLevel).
+		// docs
+		Doc("set level properties").
+		Operation("setLevel").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Param(service2.QueryParameter("rescaleHeights",
+			"Whether tile heights are converted to keep their physical height if the height shift changes; Default: true").DataType("boolean")).
+		Reads(model.LevelProperties{}).
+		Writes(model.Level{}))
+
+	service2.Route(service2.PATCH("{project-id}/archive/levels/{level-id}").To(resource.patchLevel).
+		// docs
+		Doc("modify level properties with a JSON Merge Patch; "+
+			"Only a subset of RFC 7396 is supported: properties can not be removed, so a patch containing null is rejected with 400").
+		Operation("patchLevel").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Param(service2.QueryParameter("rescaleHeights",
+			"Whether tile heights are converted to keep their physical height if the height shift changes; Default: true").DataType("boolean")).
+		Consumes(mergePatchMimeType, restful.MIME_JSON).
+		Reads(model.LevelProperties{}).
+		Writes(model.Level{}))
+
 	service2.Route(service2.GET("{project-id}/archive/levels/{level-id}/textures").To(resource.getLevelTextures).
 		// docs
 		Doc("get level textures").
@@ -185,12 +434,76 @@ func NewWorkspaceResource(container *restful.Container, workspace *core.Workspac
 
 	service2.Route(service2.GET("{project-id}/archive/levels/{level-id}/tiles").To(resource.getLevelTiles).
 		// docs
-		Doc("get level tiles").
+		Doc("get level tiles; Without area, all tiles are returned").
 		Operation("getLevelTiles").
 		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
 		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Param(service2.QueryParameter("x0", "first corner of the area, horizontal").DataType("int")).
+		Param(service2.QueryParameter("y0", "first corner of the area, vertical").DataType("int")).
+		Param(service2.QueryParameter("x1", "second corner of the area, horizontal").DataType("int")).
+		Param(service2.QueryParameter("y1", "second corner of the area, vertical").DataType("int")).
+		Writes(model.Tiles{}))
+
+	service2.Route(service2.GET("{project-id}/archive/levels/{level-id}/map.png").To(resource.getLevelMapAsPng).
+		// docs
+		Doc("get the map of a level as PNG; North is at the top").
+		Operation("getLevelMapAsPng").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Param(service2.QueryParameter("scale", "Pixels per tile; Default: 4").DataType("int")).
+		Param(service2.QueryParameter("grid", "Whether tile borders are drawn; Default: false").DataType("boolean")).
+		Param(service2.QueryParameter("textures", "Whether floor textures are drawn; Default: false").DataType("boolean")).
+		Param(service2.QueryParameter("objects", "Whether object positions are marked; Default: false").DataType("boolean")).
+		Param(service2.QueryParameter("heights", "Whether floors are shaded by their height; Default: false").DataType("boolean")).
+		Produces("image/png").
+		Returns(http.StatusBadRequest, "map options are invalid", nil).
+		Returns(http.StatusNotFound, "level is unknown", nil))
+
+	service2.Route(service2.PATCH("{project-id}/archive/levels/{level-id}/tiles").To(resource.patchLevelTiles).
+		// docs
+		Doc("modify all tiles of an area with a JSON Merge Patch; "+
+			"Only a subset of RFC 7396 is supported: properties can not be removed, so a patch containing null is rejected with 400").
+		Operation("patchLevelTiles").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Param(service2.QueryParameter("x0", "first corner of the area, horizontal").DataType("int")).
+		Param(service2.QueryParameter("y0", "first corner of the area, vertical").DataType("int")).
+		Param(service2.QueryParameter("x1", "second corner of the area, horizontal").DataType("int")).
+		Param(service2.QueryParameter("y1", "second corner of the area, vertical").DataType("int")).
+		Consumes(mergePatchMimeType, restful.MIME_JSON).
+		Reads(model.TileProperties{}).
+		Writes(model.Tiles{}))
+
+	service2.Route(service2.POST("{project-id}/archive/levels/{level-id}/tiles/copy").To(resource.copyLevelTiles).
+		// docs
+		Doc("copy an area of tiles to another place, level or project; optionally rotated or mirrored; " +
+			"Open tiles with floor or ceiling texture can not be mirrored").
+		Operation("copyLevelTiles").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Reads(TileRegionCopy{}).
 		Writes(model.Tiles{}))
 
+	service2.Route(service2.POST("{project-id}/archive/levels/{level-id}/tiles/fill").To(resource.fillLevelTiles).
+		// docs
+		Doc("change all tiles connected to a start tile; " +
+			"The properties are a JSON Merge Patch without null, as properties can not be removed").
+		Operation("fillLevelTiles").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Reads(TileFill{}).
+		Writes(TileChanges{}))
+
+	service2.Route(service2.POST("{project-id}/archive/levels/{level-id}/tiles/replace").To(resource.replaceLevelTiles).
+		// docs
+		Doc("change all tiles of a level that match given properties; " +
+			"The properties are a JSON Merge Patch without null, as properties can not be removed").
+		Operation("replaceLevelTiles").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Reads(TileReplace{}).
+		Writes(TileChanges{}))
+
 	service2.Route(service2.GET("{project-id}/archive/levels/{level-id}/tiles/{y}/{x}").To(resource.getLevelTile).
 		// docs
 		Doc("get level tile").
@@ -212,14 +525,61 @@ func NewWorkspaceResource(container *restful.Container, workspace *core.Workspac
 		Reads(model.TileProperties{}).
 		Writes(model.Tile{}))
 
+	service2.Route(service2.PATCH("{project-id}/archive/levels/{level-id}/tiles/{y}/{x}").To(resource.patchLevelTile).
+		// docs
+		Doc("modify level tile with a JSON Merge Patch; "+
+			"Only a subset of RFC 7396 is supported: properties can not be removed, so a patch containing null is rejected with 400").
+		Operation("patchLevelTile").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Param(service2.PathParameter("y", "Y coordinate of the tile").DataType("int")).
+		Param(service2.PathParameter("x", "X coordinate of the tile").DataType("int")).
+		Consumes(mergePatchMimeType, restful.MIME_JSON).
+		Reads(model.TileProperties{}).
+		Writes(model.Tile{}))
+
 	service2.Route(service2.GET("{project-id}/archive/levels/{level-id}/objects").To(resource.getLevelObjects).
 		// docs
 		Doc("get level objects").
 		Operation("getLevelObjects").
 		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
 		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Param(service2.QueryParameter("x0", "first corner of the area, horizontal").DataType("int")).
+		Param(service2.QueryParameter("y0", "first corner of the area, vertical").DataType("int")).
+		Param(service2.QueryParameter("x1", "second corner of the area, horizontal").DataType("int")).
+		Param(service2.QueryParameter("y1", "second corner of the area, vertical").DataType("int")).
+		Param(service2.QueryParameter("cx", "center of the radius search, horizontal").DataType("int")).
+		Param(service2.QueryParameter("cy", "center of the radius search, vertical").DataType("int")).
+		Param(service2.QueryParameter("radius", "radius of the radius search").DataType("int")).
+		Param(service2.QueryParameter("units", "units of the coordinates: tile or fine; Default: tile").DataType("string")).
+		Param(service2.QueryParameter("class", "only objects of this class").DataType("int")).
+		Param(service2.QueryParameter("subclass", "only objects of this subclass").DataType("int")).
 		Writes(model.LevelObjects{}))
 
+	service2.Route(service2.GET("{project-id}/archive/levels/{level-id}/validate/objects").To(resource.validateLevelObjects).
+		// docs
+		Doc("check level objects for broken references and bad placement").
+		Operation("validateLevelObjects").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Writes(LevelObjectIssues{}))
+
+	service2.Route(service2.GET("{project-id}/archive/levels/{level-id}/logic").To(resource.getLevelLogic).
+		// docs
+		Doc("get the graph of which level objects activate or contain which").
+		Operation("getLevelLogic").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Writes(LogicGraph{}))
+
+	service2.Route(service2.GET("{project-id}/archive/levels/{level-id}/logic.dot").To(resource.getLevelLogicAsDot).
+		// docs
+		Doc("get the logic graph of a level in Graphviz DOT language").
+		Operation("getLevelLogicAsDot").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Produces(dotMimeType))
+
 	service2.Route(service2.POST("{project-id}/archive/levels/{level-id}/objects").To(resource.createLevelObject).
 		// docs
 		Doc("create a new level object").
@@ -229,8 +589,84 @@ func NewWorkspaceResource(container *restful.Container, workspace *core.Workspac
 		Reads(model.LevelObjectTemplate{}).
 		Writes(0))
 
+	service2.Route(service2.POST("{project-id}/archive/levels/{level-id}/objects/batch").To(resource.applyLevelObjectBatch).
+		// docs
+		Doc("apply a batch of level object operations; A failed batch is undone, but not atomically: "+
+			"deleted objects may be restored under new IDs, which is reported as rollback errors").
+		Operation("applyLevelObjectBatch").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Reads(LevelObjectBatch{}).
+		Writes(LevelObjectBatchResult{}).
+		Returns(http.StatusBadRequest, "batch is invalid", OperationErrors{}).
+		Returns(http.StatusConflict, "batch failed and was undone", OperationErrors{}).
+		Returns(http.StatusInternalServerError, "batch failed and could not be undone completely", OperationErrors{}))
+
+	service2.Route(service2.POST("{project-id}/archive/levels/{level-id}/objects/copy").To(resource.copyLevelObjects).
+		// docs
+		Doc("copy level objects to another place, level or project").
+		Operation("copyLevelObjects").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Reads(LevelObjectCopy{}).
+		Writes(LevelObjectCopyResult{}))
+
+	service2.Route(service2.GET("{project-id}/archive/levels/{level-id}/objects/{object-id}").To(resource.getLevelObject).
+		// docs
+		Doc("get level object").
+		Operation("getLevelObject").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Param(service2.PathParameter("object-id", "identifier of the level object").DataType("int")).
+		Writes(model.LevelObject{}))
+
+	service2.Route(service2.PUT("{project-id}/archive/levels/{level-id}/objects/{object-id}").To(resource.setLevelObject).
+		// docs
+		Doc("set level object; The position must be given completely").
+		Operation("setLevelObject").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Param(service2.PathParameter("object-id", "identifier of the level object").DataType("int")).
+		Reads(model.LevelObjectProperties{}).
+		Writes(model.LevelObject{}))
+
+	service2.Route(service2.PATCH("{project-id}/archive/levels/{level-id}/objects/{object-id}").To(resource.patchLevelObject).
+		// docs
+		Doc("modify level object; Only the given properties are changed").
+		Operation("patchLevelObject").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Param(service2.PathParameter("object-id", "identifier of the level object").DataType("int")).
+		Reads(model.LevelObjectProperties{}).
+		Writes(model.LevelObject{}))
+
+	service2.Route(service2.DELETE("{project-id}/archive/levels/{level-id}/objects/{object-id}").To(resource.deleteLevelObject).
+		// docs
+		Doc("delete level object").
+		Operation("deleteLevelObject").
+		Param(service2.PathParameter("project-id", "identifier of the project").DataType("string")).
+		Param(service2.PathParameter("level-id", "identifier of the level").DataType("int")).
+		Param(service2.PathParameter("object-id", "identifier of the level object").DataType("int")))
+
 	container.Add(service2)
 
+	service3 := new(restful.WebService)
+
+	service3.
+		Path("/schemas").
+		Doc("Describe data structures").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+
+	service3.Route(service3.GET("objects/{class}").To(resource.getObjectClassSchema).
+		// docs
+		Doc("get JSON schema of the properties of an object class").
+		Operation("getObjectClassSchema").
+		Param(service3.PathParameter("class", "identifier of the class").DataType("int")).
+		Writes(JSONSchema{}))
+
+	container.Add(service3)
+
 	return resource
 }
 
@@ -244,6 +680,18 @@ func (resource *WorkspaceResource) getWorkspace(request *restful.Request, respon
 	response.WriteEntity(entity)
 }
 
+// GET /schemas/objects/{class}
+func (resource *WorkspaceResource) getObjectClassSchema(request *restful.Request, response *restful.Response) {
+	classID, err := strconv.ParseInt(request.PathParameter("class"), 10, 8)
+
+	if (err == nil) && isValidObjectClass(int(classID)) {
+		response.WriteEntity(objectClassSchema(int(classID)))
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, "Unknown object class")
+	}
+}
+
 // GET /projects
 func (resource *WorkspaceResource) getProjects(request *restful.Request, response *restful.Response) {
 	projectNames := resource.ws.ProjectNames()
@@ -293,9 +741,9 @@ func (resource *WorkspaceResource) getPalette(request *restful.Request, response
 		paletteID := request.PathParameter("palette-id")
 		var palette color.Palette
 
-		palette, err = project.Palettes().GamePalette()
+		palette, err = resource.palette(project, paletteID)
 
-		if paletteID == "game" && err == nil {
+		if err == nil {
 			var entity model.Palette
 
 			resource.encodePalette(&entity.Colors, palette)
@@ -311,6 +759,20 @@ func (resource *WorkspaceResource) getPalette(request *restful.Request, response
 	}
 }
 
+// palette returns the palette with given identifier. The identifier is either "game" for the
+// game palette, or the resource ID of a palette, in decimal or hexadecimal (0x) notation.
+func (resource *WorkspaceResource) palette(project *core.Project, paletteID string) (palette color.Palette, err error) {
+	if paletteID == "game" {
+		palette, err = project.Palettes().GamePalette()
+	} else if resourceID, parseErr := strconv.ParseUint(paletteID, 0, 16); parseErr == nil {
+		palette, err = project.Palettes().Palette(res.ResourceID(resourceID))
+	} else {
+		err = fmt.Errorf("Unknown palette")
+	}
+
+	return
+}
+
 func (resource *WorkspaceResource) encodePalette(out *[256]model.Color, palette color.Palette) {
 	for index, inColor := range palette {
 		outColor := &out[index]
@@ -368,6 +830,74 @@ func (resource *WorkspaceResource) getTextures(request *restful.Request, respons
 	}
 }
 
+// GET /projects/{project-id}/textures/duplicates
+func (resource *WorkspaceResource) getTextureDuplicates(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		textureSize := model.TextureSize(request.QueryParameter("size"))
+		threshold := int64(defaultDuplicateThreshold)
+		var palette color.Palette
+
+		if textureSize == "" {
+			textureSize = "large"
+		}
+		if thresholdParam := request.QueryParameter("threshold"); thresholdParam != "" {
+			threshold, err = strconv.ParseInt(thresholdParam, 10, 8)
+			if (err == nil) && ((threshold < 0) || (threshold > maxDuplicateThreshold)) {
+				err = fmt.Errorf("Threshold must be between 0 and %d", maxDuplicateThreshold)
+			}
+			if err != nil {
+				response.AddHeader("Content-Type", "text/plain")
+				response.WriteErrorString(http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		palette, err = project.Palettes().GamePalette()
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		textures := project.Textures()
+		limit := textures.TextureCount()
+		images := make([]*goimage.Paletted, limit)
+		hashes := make([]perceptualHash, limit)
+		for id := 0; id < limit; id++ {
+			images[id] = palettedImage(textures.Image(id, textureSize), palette)
+			hashes[id] = hashImage(images[id])
+		}
+
+		var entity TextureDuplicates
+		entity.Href = "/projects/" + projectID + "/textures/duplicates"
+		entity.Clusters = []TextureCluster{}
+		for _, members := range clusterHashes(hashes, int(threshold)) {
+			var cluster TextureCluster
+			nearest := nearestMembers(hashes, members)
+
+			for index, id := range members {
+				var entry TextureSimilarity
+				other := nearest[index]
+				entry.ID = fmt.Sprintf("%d", id)
+				entry.Href = "/projects/" + projectID + "/textures/" + entry.ID
+				entry.NearestID = fmt.Sprintf("%d", other)
+				entry.Similarity = hashes[id].similarity(hashes[other])
+				entry.Identical = images[other].Rect.Eq(images[id].Rect) && bytes.Equal(images[other].Pix, images[id].Pix)
+				cluster.Textures = append(cluster.Textures, entry)
+			}
+			entity.Clusters = append(entity.Clusters, cluster)
+		}
+
+		response.WriteEntity(entity)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
 // GET /projects/{project-id}/textures/{texture-id}
 func (resource *WorkspaceResource) getTexture(request *restful.Request, response *restful.Response) {
 	projectID := request.PathParameter("project-id")
@@ -392,6 +922,7 @@ func (resource *WorkspaceResource) setTexture(request *restful.Request, response
 
 	if err == nil {
 		textureID, _ := strconv.ParseInt(request.PathParameter("texture-id"), 10, 16)
+		defer resource.locks.lock(textureLockKey(projectID, int(textureID)))()
 		var properties model.TextureProperties
 		err = request.ReadEntity(&properties)
 		if err != nil {
@@ -411,38 +942,29 @@ func (resource *WorkspaceResource) setTexture(request *restful.Request, response
 	}
 }
 
-func (resource *WorkspaceResource) textureEntity(project *core.Project, textureID int) (entity model.Texture) {
-	entity.ID = fmt.Sprintf("%d", textureID)
-	entity.Href = "/projects/" + project.Name() + "/textures/" + entity.ID
-	entity.Properties = project.Textures().Properties(textureID)
-	for _, size := range model.TextureSizes() {
-		entity.Images = append(entity.Images, model.Link{Rel: string(size), Href: entity.Href + "/" + string(size)})
-	}
-
-	return
-}
-
-// GET /projects/{project-id}/textures/{texture-id}/{texture-size}
-func (resource *WorkspaceResource) getTextureImage(request *restful.Request, response *restful.Response) {
+// PATCH /projects/{project-id}/textures/{texture-id}
+func (resource *WorkspaceResource) patchTexture(request *restful.Request, response *restful.Response) {
 	projectID := request.PathParameter("project-id")
 	project, err := resource.ws.Project(projectID)
 
 	if err == nil {
 		textureID, _ := strconv.ParseInt(request.PathParameter("texture-id"), 10, 16)
-		textureSize := request.PathParameter("texture-size")
-		var entity model.Image
+		defer resource.locks.lock(textureLockKey(projectID, int(textureID)))()
+		var properties model.TextureProperties
+		var patch interface{}
 
-		entity.Href = "/projects/" + projectID + "/textures/" + fmt.Sprintf("%d", textureID) + "/" + textureSize
-		bmp := project.Textures().Image(int(textureID), model.TextureSize(textureSize))
-		hotspot := bmp.Hotspot()
-
-		entity.Properties.HotspotLeft = hotspot.Min.X
-		entity.Properties.HotspotTop = hotspot.Min.Y
-		entity.Properties.HotspotRight = hotspot.Max.X
-		entity.Properties.HotspotBottom = hotspot.Max.Y
+		patch, err = readMergePatch(request.Request.Body)
+		if err == nil {
+			err = applyMergePatch(project.Textures().Properties(int(textureID)), patch, &properties)
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
 
-		entity.Formats = []model.Link{model.Link{Rel: "png", Href: entity.Href + "/png"}}
-		entity.Formats = []model.Link{model.Link{Rel: "raw", Href: entity.Href + "/raw"}}
+		project.Textures().SetProperties(int(textureID), properties)
+		entity := resource.textureEntity(project, int(textureID))
 
 		response.WriteEntity(entity)
 	} else {
@@ -452,27 +974,148 @@ func (resource *WorkspaceResource) getTextureImage(request *restful.Request, res
 	}
 }
 
-// GET /projects/{project-id}/textures/{texture-id}/{texture-size}/raw
-func (resource *WorkspaceResource) getTextureImageAsRaw(request *restful.Request, response *restful.Response) {
+func (resource *WorkspaceResource) textureEntity(project *core.Project, textureID int) (entity model.Texture) {
+	entity.ID = fmt.Sprintf("%d", textureID)
+	entity.Href = "/projects/" + project.Name() + "/textures/" + entity.ID
+	entity.Properties = project.Textures().Properties(textureID)
+	for _, size := range model.TextureSizes() {
+		entity.Images = append(entity.Images, model.Link{Rel: string(size), Href: entity.Href + "/" + string(size)})
+	}
+	if isAnimatedTexture(entity.Properties) {
+		entity.Images = append(entity.Images, model.Link{Rel: "animation", Href: entity.Href + "/animation.gif"})
+	}
+
+	return
+}
+
+func isAnimatedTexture(properties model.TextureProperties) bool {
+	return (properties.AnimationGroup != nil) && (*properties.AnimationGroup != 0)
+}
+
+// textureAnimationFrames returns the identifiers of all textures that share the animation group
+// of the given texture, ordered by their animation index. A texture without an animation group
+// is returned as the only frame.
+func (resource *WorkspaceResource) textureAnimationFrames(project *core.Project, textureID int) []int {
+	textures := project.Textures()
+	properties := textures.Properties(textureID)
+	frames := []int{textureID}
+
+	if isAnimatedTexture(properties) {
+		group := *properties.AnimationGroup
+		indices := map[int]int{}
+
+		frames = nil
+		for id := 0; id < textures.TextureCount(); id++ {
+			other := textures.Properties(id)
+			if isAnimatedTexture(other) && (*other.AnimationGroup == group) {
+				frames = append(frames, id)
+				if other.AnimationIndex != nil {
+					indices[id] = *other.AnimationIndex
+				}
+			}
+		}
+		sort.SliceStable(frames, func(a, b int) bool { return indices[frames[a]] < indices[frames[b]] })
+	}
+
+	return frames
+}
+
+// GET /projects/{project-id}/textures/{texture-id}/{texture-size}
+func (resource *WorkspaceResource) getTextureImage(request *restful.Request, response *restful.Response) {
 	projectID := request.PathParameter("project-id")
 	project, err := resource.ws.Project(projectID)
 
 	if err == nil {
 		textureID, _ := strconv.ParseInt(request.PathParameter("texture-id"), 10, 16)
 		textureSize := request.PathParameter("texture-size")
+		href := "/projects/" + projectID + "/textures/" + fmt.Sprintf("%d", textureID) + "/" + textureSize
 		bmp := project.Textures().Image(int(textureID), model.TextureSize(textureSize))
-		var entity model.RawBitmap
 
-		entity.Width = int(bmp.ImageWidth())
-		entity.Height = int(bmp.ImageHeight())
-		var pixel []byte
+		response.WriteEntity(imageEntity(href, bmp, "png", "raw"))
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// PUT /projects/{project-id}/textures/{texture-id}/{texture-size}
+func (resource *WorkspaceResource) setTextureImage(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		textureID, _ := strconv.ParseInt(request.PathParameter("texture-id"), 10, 16)
+		textureSize := request.PathParameter("texture-size")
+		href := "/projects/" + projectID + "/textures/" + fmt.Sprintf("%d", textureID) + "/" + textureSize
+		var properties model.ImageProperties
 
-		for row := 0; row < entity.Height; row++ {
-			pixel = append(pixel, bmp.Row(row)...)
+		defer resource.locks.lock(textureLockKey(projectID, int(textureID)))()
+		err = request.ReadEntity(&properties)
+		if err == nil {
+			err = validateImageProperties(properties, project.Textures().Image(int(textureID), model.TextureSize(textureSize)))
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
 		}
-		entity.Pixels = base64.StdEncoding.EncodeToString(pixel)
 
-		response.WriteEntity(entity)
+		project.Textures().SetImageProperties(int(textureID), model.TextureSize(textureSize), properties)
+		bmp := project.Textures().Image(int(textureID), model.TextureSize(textureSize))
+
+		response.WriteEntity(imageEntity(href, bmp, "png", "raw"))
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// imageEntity describes the given bitmap, with format links relative to href.
+func imageEntity(href string, bmp image.Bitmap, formats ...string) (entity model.Image) {
+	hotspot := bmp.Hotspot()
+
+	entity.Href = href
+	entity.Properties.HotspotLeft = hotspot.Min.X
+	entity.Properties.HotspotTop = hotspot.Min.Y
+	entity.Properties.HotspotRight = hotspot.Max.X
+	entity.Properties.HotspotBottom = hotspot.Max.Y
+
+	entity.Formats = []model.Link{}
+	for _, format := range formats {
+		entity.Formats = append(entity.Formats, model.Link{Rel: format, Href: entity.Href + "/" + format})
+	}
+
+	return
+}
+
+// validateImageProperties checks that the hotspot is an ordered area within the given bitmap.
+func validateImageProperties(properties model.ImageProperties, bmp image.Bitmap) (err error) {
+	if properties.HotspotLeft > properties.HotspotRight {
+		err = fmt.Errorf("Hotspot left must not be greater than right")
+	} else if properties.HotspotTop > properties.HotspotBottom {
+		err = fmt.Errorf("Hotspot top must not be greater than bottom")
+	} else if (properties.HotspotLeft < 0) || (properties.HotspotTop < 0) {
+		err = fmt.Errorf("Hotspot must not be negative")
+	} else if (properties.HotspotRight > int(bmp.ImageWidth())) || (properties.HotspotBottom > int(bmp.ImageHeight())) {
+		err = fmt.Errorf("Hotspot must be within the image size of %dx%d", bmp.ImageWidth(), bmp.ImageHeight())
+	}
+
+	return
+}
+
+// GET /projects/{project-id}/textures/{texture-id}/{texture-size}/raw
+func (resource *WorkspaceResource) getTextureImageAsRaw(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		textureID, _ := strconv.ParseInt(request.PathParameter("texture-id"), 10, 16)
+		textureSize := request.PathParameter("texture-size")
+		bmp := project.Textures().Image(int(textureID), model.TextureSize(textureSize))
+
+		writeRawBitmap(request, response, bmp)
 	} else {
 		response.AddHeader("Content-Type", "text/plain")
 		response.WriteErrorString(http.StatusBadRequest, err.Error())
@@ -480,6 +1123,32 @@ func (resource *WorkspaceResource) getTextureImageAsRaw(request *restful.Request
 	}
 }
 
+// writeRawBitmap writes the pixel data of given bitmap either as a base64 encoded
+// model.RawBitmap, or - if the Accept header of the client prefers an octet stream - as
+// plain bytes with the dimensions in the response headers.
+func writeRawBitmap(request *restful.Request, response *restful.Response, bmp image.Bitmap) {
+	width := int(bmp.ImageWidth())
+	height := int(bmp.ImageHeight())
+	pixel := rawPixels(bmp)
+
+	offered := []string{restful.MIME_JSON, restful.MIME_XML, restful.MIME_OCTET}
+	if preferredMediaType(request.HeaderParameter("Accept"), offered...) == restful.MIME_OCTET {
+		response.AddHeader("Content-Type", restful.MIME_OCTET)
+		response.AddHeader("Content-Length", strconv.Itoa(len(pixel)))
+		response.AddHeader(imageWidthHeader, strconv.Itoa(width))
+		response.AddHeader(imageHeightHeader, strconv.Itoa(height))
+		response.Write(pixel)
+	} else {
+		var entity model.RawBitmap
+
+		entity.Width = width
+		entity.Height = height
+		entity.Pixels = base64.StdEncoding.EncodeToString(pixel)
+
+		response.WriteEntity(entity)
+	}
+}
+
 // GET /projects/{project-id}/textures/{texture-id}/{texture-size}/png
 func (resource *WorkspaceResource) getTextureImageAsPng(request *restful.Request, response *restful.Response) {
 	projectID := request.PathParameter("project-id")
@@ -488,15 +1157,77 @@ func (resource *WorkspaceResource) getTextureImageAsPng(request *restful.Request
 	if err == nil {
 		textureID, _ := strconv.ParseInt(request.PathParameter("texture-id"), 10, 16)
 		textureSize := request.PathParameter("texture-size")
+		bmp := project.Textures().Image(int(textureID), model.TextureSize(textureSize))
+
+		resource.writePng(request, response, project, bmp)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// writePng encodes given bitmap as PNG, according to the image options of the request.
+func (resource *WorkspaceResource) writePng(request *restful.Request, response *restful.Response,
+	project *core.Project, bmp image.Bitmap) {
+	options, err := imageOptionsFromRequest(request)
+	var palette color.Palette
+
+	if err == nil {
+		palette, err = resource.palette(project, options.paletteID)
+	}
+	if err != nil {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.AddHeader("Content-Type", "image/png")
+	png.Encode(response.ResponseWriter, options.apply(palettedImage(bmp, palette)))
+}
+
+// GET /projects/{project-id}/textures/{texture-id}/animation.gif
+func (resource *WorkspaceResource) getTextureAnimationAsGif(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		textureID, _ := strconv.ParseInt(request.PathParameter("texture-id"), 10, 16)
+		textureSize := request.QueryParameter("size")
+		delay := defaultTextureAnimationFrameDelay
 		var palette color.Palette
 
-		bmp := project.Textures().Image(int(textureID), model.TextureSize(textureSize))
+		if textureSize == "" {
+			textureSize = "large"
+		}
+		if delayParam := request.QueryParameter("delay"); delayParam != "" {
+			delay, err = strconv.Atoi(delayParam)
+			if (err == nil) && ((delay < 1) || (delay > maxTextureAnimationFrameDelay)) {
+				err = fmt.Errorf("Delay must be between 1 and %d", maxTextureAnimationFrameDelay)
+			}
+			if err != nil {
+				response.AddHeader("Content-Type", "text/plain")
+				response.WriteErrorString(http.StatusBadRequest, err.Error())
+				return
+			}
+		}
 		palette, err = project.Palettes().GamePalette()
-		image := image.FromBitmap(bmp, palette)
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusInternalServerError, err.Error())
+			return
+		}
 
-		response.AddHeader("Content-Type", "image/png")
-		png.Encode(response.ResponseWriter, image)
+		var animation gif.GIF
+		for _, frameID := range resource.textureAnimationFrames(project, int(textureID)) {
+			bmp := project.Textures().Image(frameID, model.TextureSize(textureSize))
+
+			animation.Image = append(animation.Image, palettedImage(bmp, palette))
+			animation.Delay = append(animation.Delay, delay)
+		}
 
+		response.AddHeader("Content-Type", "image/gif")
+		gif.EncodeAll(response.ResponseWriter, &animation)
 	} else {
 		response.AddHeader("Content-Type", "text/plain")
 		response.WriteErrorString(http.StatusBadRequest, err.Error())
@@ -546,6 +1277,71 @@ func (resource *WorkspaceResource) getLevel(request *restful.Request, response *
 	}
 }
 
+// PUT /projects/{project-id}/archive/levels/{level-id}
+func (resource *WorkspaceResource) setLevel(request *restful.Request, response *restful.Response) {
+	resource.modifyLevel(request, response, false)
+}
+
+// PATCH /projects/{project-id}/archive/levels/{level-id}
+func (resource *WorkspaceResource) patchLevel(request *restful.Request, response *restful.Response) {
+	resource.modifyLevel(request, response, true)
+}
+
+// modifyLevel updates the properties of a level from the request. If merge is set, the request
+// contains a JSON Merge Patch for the current properties.
+func (resource *WorkspaceResource) modifyLevel(request *restful.Request, response *restful.Response, merge bool) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		defer resource.locks.lock(levelLockKey(projectID, int(levelID)))()
+		level := project.Archive().Level(int(levelID))
+		current := level.Properties()
+		rescale := true
+		var properties model.LevelProperties
+		var tiles [][]model.TileProperties
+
+		if rescaleParam := request.QueryParameter("rescaleHeights"); rescaleParam != "" {
+			rescale, err = strconv.ParseBool(rescaleParam)
+		}
+		if (err == nil) && merge {
+			var patch interface{}
+			patch, err = readMergePatch(request.Request.Body)
+			if err == nil {
+				err = applyMergePatch(current, patch, &properties)
+			}
+		} else if err == nil {
+			err = request.ReadEntity(&properties)
+		}
+		if err == nil {
+			err = validateLevelProperties(&current, &properties)
+		}
+		if (err == nil) && rescale && (properties.HeightShift != current.HeightShift) {
+			tiles, err = rescaledTileHeights(level, current.HeightShift, properties.HeightShift)
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		level.SetProperties(properties)
+		for y, row := range tiles {
+			for x, tileProperties := range row {
+				level.SetTileProperties(x, y, tileProperties)
+			}
+		}
+		entity := resource.getLevelEntity(project, project.Archive(), int(levelID))
+
+		response.WriteEntity(entity)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
 func (resource *WorkspaceResource) getLevelEntity(project *core.Project, archive *core.Archive, levelID int) (entity model.Level) {
 	entity.ID = fmt.Sprintf("%d", levelID)
 	entity.Href = "/projects/" + project.Name() + "/archive/levels/" + entity.ID
@@ -593,6 +1389,7 @@ func (resource *WorkspaceResource) setLevelTextures(request *restful.Request, re
 
 	if err == nil {
 		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		defer resource.locks.lock(levelLockKey(projectID, int(levelID)))()
 
 		var ids []int
 		err = request.ReadEntity(&ids)
@@ -622,17 +1419,14 @@ func (resource *WorkspaceResource) getLevelTiles(request *restful.Request, respo
 	if err == nil {
 		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
 		level := project.Archive().Level(int(levelID))
-		var entity model.Tiles
+		area, _, areaErr := tileAreaFromRequest(request)
 
-		entity.Table = make([][]model.Tile, 64)
-		for y := 0; y < 64; y++ {
-			entity.Table[y] = make([]model.Tile, 64)
-			for x := 0; x < 64; x++ {
-				entity.Table[y][x] = getLevelTileEntity(project, level, x, y)
-			}
+		if areaErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, areaErr.Error())
+			return
 		}
-
-		response.WriteEntity(entity)
+		response.WriteEntity(getLevelTilesEntity(project, level, area))
 	} else {
 		response.AddHeader("Content-Type", "text/plain")
 		response.WriteErrorString(http.StatusBadRequest, err.Error())
@@ -640,26 +1434,52 @@ func (resource *WorkspaceResource) getLevelTiles(request *restful.Request, respo
 	}
 }
 
-func getLevelTileEntity(project *core.Project, level *core.Level, x int, y int) (entity model.Tile) {
-	entity.Href = "/projects/" + project.Name() + "/archive/levels/" + fmt.Sprintf("%d", level.ID()) +
-		"/tiles/" + fmt.Sprintf("%d", y) + "/" + fmt.Sprintf("%d", x)
-	entity.Properties = level.TileProperties(int(x), int(y))
+func getLevelTilesEntity(project *core.Project, level *core.Level, area TileArea) (entity model.Tiles) {
+	entity.Table = make([][]model.Tile, area.Y1-area.Y0+1)
+	for y := area.Y0; y <= area.Y1; y++ {
+		row := make([]model.Tile, area.X1-area.X0+1)
+		for x := area.X0; x <= area.X1; x++ {
+			row[x-area.X0] = getLevelTileEntity(project, level, x, y)
+		}
+		entity.Table[y-area.Y0] = row
+	}
 
 	return
 }
 
-// GET /projects/{project-id}/archive/levels/{level-id}/tiles/{y}/{x}
-func (resource *WorkspaceResource) getLevelTile(request *restful.Request, response *restful.Response) {
+// GET /projects/{project-id}/archive/levels/{level-id}/map.png
+func (resource *WorkspaceResource) getLevelMapAsPng(request *restful.Request, response *restful.Response) {
 	projectID := request.PathParameter("project-id")
 	project, err := resource.ws.Project(projectID)
 
 	if err == nil {
-		x, _ := strconv.ParseInt(request.PathParameter("x"), 10, 16)
-		y, _ := strconv.ParseInt(request.PathParameter("y"), 10, 16)
-		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		levelID, levelErr := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		if (levelErr != nil) || !isKnownLevel(project.Archive(), int(levelID)) {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusNotFound, "Unknown level")
+			return
+		}
 		level := project.Archive().Level(int(levelID))
 
-		response.WriteEntity(getLevelTileEntity(project, level, int(x), int(y)))
+		options, optionsErr := levelMapOptionsFromRequest(request)
+		if optionsErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, optionsErr.Error())
+			return
+		}
+
+		var buffer bytes.Buffer
+		palette, renderErr := project.Palettes().GamePalette()
+		if renderErr == nil {
+			renderErr = png.Encode(&buffer, renderLevelMap(project, level, options, palette))
+		}
+		if renderErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusInternalServerError, renderErr.Error())
+			return
+		}
+		response.AddHeader("Content-Type", "image/png")
+		response.Write(buffer.Bytes())
 	} else {
 		response.AddHeader("Content-Type", "text/plain")
 		response.WriteErrorString(http.StatusBadRequest, err.Error())
@@ -667,27 +1487,51 @@ func (resource *WorkspaceResource) getLevelTile(request *restful.Request, respon
 	}
 }
 
-// PUT /projects/{project-id}/archive/levels/{level-id}/tiles/{y}/{x}
-func (resource *WorkspaceResource) setLevelTile(request *restful.Request, response *restful.Response) {
+// PATCH /projects/{project-id}/archive/levels/{level-id}/tiles
+func (resource *WorkspaceResource) patchLevelTiles(request *restful.Request, response *restful.Response) {
 	projectID := request.PathParameter("project-id")
 	project, err := resource.ws.Project(projectID)
 
 	if err == nil {
-		x, _ := strconv.ParseInt(request.PathParameter("x"), 10, 16)
-		y, _ := strconv.ParseInt(request.PathParameter("y"), 10, 16)
 		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		defer resource.locks.lock(levelLockKey(projectID, int(levelID)))()
 		level := project.Archive().Level(int(levelID))
+		area, areaGiven, areaErr := tileAreaFromRequest(request)
+		var patch interface{}
 
-		var properties model.TileProperties
-		err = request.ReadEntity(&properties)
+		if (areaErr == nil) && !areaGiven {
+			areaErr = fmt.Errorf("Area is required")
+		}
+		if areaErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, areaErr.Error())
+			return
+		}
+		patch, err = readMergePatch(request.Request.Body)
 		if err != nil {
 			response.AddHeader("Content-Type", "text/plain")
-			response.WriteErrorString(http.StatusInternalServerError, err.Error())
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
 			return
 		}
 
-		level.SetTileProperties(int(x), int(y), properties)
-		response.WriteEntity(getLevelTileEntity(project, level, int(x), int(y)))
+		patched := make(map[[2]int]model.TileProperties)
+		for y := area.Y0; (err == nil) && (y <= area.Y1); y++ {
+			for x := area.X0; (err == nil) && (x <= area.X1); x++ {
+				var properties model.TileProperties
+				err = applyMergePatch(level.TileProperties(x, y), patch, &properties)
+				patched[[2]int{x, y}] = properties
+			}
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+		for coord, properties := range patched {
+			level.SetTileProperties(coord[0], coord[1], properties)
+		}
+
+		response.WriteEntity(getLevelTilesEntity(project, level, area))
 	} else {
 		response.AddHeader("Content-Type", "text/plain")
 		response.WriteErrorString(http.StatusBadRequest, err.Error())
@@ -695,62 +1539,888 @@ func (resource *WorkspaceResource) setLevelTile(request *restful.Request, respon
 	}
 }
 
-// GET /projects/{project-id}/archive/levels/{level-id}/objects
-func (resource *WorkspaceResource) getLevelObjects(request *restful.Request, response *restful.Response) {
-	projectID := request.PathParameter("project-id")
-	project, err := resource.ws.Project(projectID)
-
-	if err == nil {
-		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
-		level := project.Archive().Level(int(levelID))
-		hrefBase := "/projects/" + projectID + "/archive/levels/" + fmt.Sprintf("%d", levelID) + "/objects/"
-		var entity model.LevelObjects
-
-		entity.Table = level.Objects()
-		for i := 0; i < len(entity.Table); i++ {
-			entry := &entity.Table[i]
-			entry.Href = hrefBase + entry.ID
-
-			entry.Links = append(entry.Links, model.Link{
-				Rel:  "static",
-				Href: "/projects/" + projectID + "/objects/" + fmt.Sprintf("%d/%d/%d", entry.Class, entry.Subclass, entry.Type)})
-		}
+func getLevelTileEntity(project *core.Project, level *core.Level, x int, y int) (entity model.Tile) {
+	entity.Href = "/projects/" + project.Name() + "/archive/levels/" + fmt.Sprintf("%d", level.ID()) +
+		"/tiles/" + fmt.Sprintf("%d", y) + "/" + fmt.Sprintf("%d", x)
+	entity.Properties = level.TileProperties(int(x), int(y))
 
-		response.WriteEntity(entity)
-	} else {
-		response.AddHeader("Content-Type", "text/plain")
-		response.WriteErrorString(http.StatusBadRequest, err.Error())
-		return
-	}
+	return
 }
 
-// GET /projects/{project-id}/archive/levels/{level-id}/objects
-func (resource *WorkspaceResource) createLevelObject(request *restful.Request, response *restful.Response) {
+// POST /projects/{project-id}/archive/levels/{level-id}/tiles/copy
+func (resource *WorkspaceResource) copyLevelTiles(request *restful.Request, response *restful.Response) {
 	projectID := request.PathParameter("project-id")
 	project, err := resource.ws.Project(projectID)
 
 	if err == nil {
-		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
-		level := project.Archive().Level(int(levelID))
+		levelID, levelErr := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		var copyRequest TileRegionCopy
+		var transform tileTransform
+		var targetArea TileArea
 
-		entityTemplate := new(model.LevelObjectTemplate)
-		err = request.ReadEntity(entityTemplate)
-
-		if err != nil {
+		if (levelErr != nil) || !isKnownLevel(project.Archive(), int(levelID)) {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, "Unknown level")
+			return
+		}
+		level := project.Archive().Level(int(levelID))
+		err = request.ReadEntity(&copyRequest)
+		targetProject := project
+		if (err == nil) && (copyRequest.TargetProject != "") {
+			targetProject, err = resource.ws.Project(copyRequest.TargetProject)
+		}
+		if (err == nil) && !isKnownLevel(targetProject.Archive(), copyRequest.TargetLevel) {
+			err = fmt.Errorf("Unknown target level %d", copyRequest.TargetLevel)
+		}
+		if err == nil {
+			area := &copyRequest.Area
+			area.X0, area.X1 = minInt(area.X0, area.X1), maxInt(area.X0, area.X1)
+			area.Y0, area.Y1 = minInt(area.Y0, area.Y1), maxInt(area.Y0, area.Y1)
+			transform, err = newTileTransform(*area, copyRequest.Rotation, copyRequest.Mirror)
+		}
+		if err == nil {
+			width, height := transform.size()
+			targetArea = TileArea{X0: copyRequest.TargetX, Y0: copyRequest.TargetY,
+				X1: copyRequest.TargetX + width - 1, Y1: copyRequest.TargetY + height - 1}
+			if !isWithinMap(copyRequest.Area) || !isWithinMap(targetArea) {
+				err = fmt.Errorf("Source and target area must be within the map")
+			}
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		defer resource.locks.lockAll(levelLockKey(projectID, int(levelID)),
+			levelLockKey(targetProject.Name(), copyRequest.TargetLevel))()
+		targetLevel := targetProject.Archive().Level(copyRequest.TargetLevel)
+		texture := levelTextureMapping(level.Textures(), targetLevel.Textures())
+		sourceShift, targetShift := level.Properties().HeightShift, targetLevel.Properties().HeightShift
+		area := copyRequest.Area
+		copied := make(map[[2]int]model.TileProperties)
+		for y := area.Y0; (err == nil) && (y <= area.Y1); y++ {
+			for x := area.X0; (err == nil) && (x <= area.X1); x++ {
+				var properties model.TileProperties
+				targetX, targetY := transform.position(x-area.X0, y-area.Y0)
+
+				properties, err = transform.tileProperties(level.TileProperties(x, y), texture)
+				if err == nil {
+					var heightsOk bool
+					properties, heightsOk = rescaledTileProperties(properties, sourceShift, targetShift)
+					if !heightsOk {
+						err = fmt.Errorf("Heights of tile %d/%d can not be kept with height shift %d of the target level",
+							x, y, targetShift)
+					}
+				}
+				copied[[2]int{targetArea.X0 + targetX, targetArea.Y0 + targetY}] = properties
+			}
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+		for coord, properties := range copied {
+			targetLevel.SetTileProperties(coord[0], coord[1], properties)
+		}
+
+		response.WriteEntity(getLevelTilesEntity(targetProject, targetLevel, targetArea))
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// POST /projects/{project-id}/archive/levels/{level-id}/tiles/fill
+func (resource *WorkspaceResource) fillLevelTiles(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		defer resource.locks.lock(levelLockKey(projectID, int(levelID)))()
+		level := project.Archive().Level(int(levelID))
+		var fill TileFill
+		var sameArea func(x, y int) bool
+		var entity TileChanges
+
+		err = request.ReadEntity(&fill)
+		if (err == nil) && !isWithinMap(TileArea{X0: fill.X, Y0: fill.Y, X1: fill.X, Y1: fill.Y}) {
+			err = fmt.Errorf("Start tile must be within the map")
+		}
+		if err == nil {
+			sameArea, err = sameTileBoundary(level, fill.X, fill.Y, fill.BoundedBy)
+		}
+		if err == nil {
+			entity.Tiles, err = patchTiles(level, floodFillArea(fill.X, fill.Y, sameArea), fill.Properties)
+		}
+		if err == nil {
+			response.WriteEntity(entity)
+		} else {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+		}
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// POST /projects/{project-id}/archive/levels/{level-id}/tiles/replace
+func (resource *WorkspaceResource) replaceLevelTiles(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		defer resource.locks.lock(levelLockKey(projectID, int(levelID)))()
+		level := project.Archive().Level(int(levelID))
+		var replace TileReplace
+		var tiles []TileCoordinate
+		var entity TileChanges
+
+		err = request.ReadEntity(&replace)
+		if err == nil {
+			tiles, err = matchingTiles(level, replace.Match)
+		}
+		if err == nil {
+			entity.Tiles, err = patchTiles(level, tiles, replace.Properties)
+		}
+		if err == nil {
+			response.WriteEntity(entity)
+		} else {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+		}
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// GET /projects/{project-id}/archive/levels/{level-id}/tiles/{y}/{x}
+func (resource *WorkspaceResource) getLevelTile(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		x, _ := strconv.ParseInt(request.PathParameter("x"), 10, 16)
+		y, _ := strconv.ParseInt(request.PathParameter("y"), 10, 16)
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		level := project.Archive().Level(int(levelID))
+
+		response.WriteEntity(getLevelTileEntity(project, level, int(x), int(y)))
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// PUT /projects/{project-id}/archive/levels/{level-id}/tiles/{y}/{x}
+func (resource *WorkspaceResource) setLevelTile(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		x, _ := strconv.ParseInt(request.PathParameter("x"), 10, 16)
+		y, _ := strconv.ParseInt(request.PathParameter("y"), 10, 16)
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		defer resource.locks.lock(levelLockKey(projectID, int(levelID)))()
+		level := project.Archive().Level(int(levelID))
+
+		var properties model.TileProperties
+		err = request.ReadEntity(&properties)
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		level.SetTileProperties(int(x), int(y), properties)
+		response.WriteEntity(getLevelTileEntity(project, level, int(x), int(y)))
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// PATCH /projects/{project-id}/archive/levels/{level-id}/tiles/{y}/{x}
+func (resource *WorkspaceResource) patchLevelTile(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		x, _ := strconv.ParseInt(request.PathParameter("x"), 10, 16)
+		y, _ := strconv.ParseInt(request.PathParameter("y"), 10, 16)
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		defer resource.locks.lock(levelLockKey(projectID, int(levelID)))()
+		level := project.Archive().Level(int(levelID))
+		var properties model.TileProperties
+		var patch interface{}
+
+		patch, err = readMergePatch(request.Request.Body)
+		if err == nil {
+			err = applyMergePatch(level.TileProperties(int(x), int(y)), patch, &properties)
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		level.SetTileProperties(int(x), int(y), properties)
+		response.WriteEntity(getLevelTileEntity(project, level, int(x), int(y)))
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// GET /projects/{project-id}/archive/levels/{level-id}/objects
+func (resource *WorkspaceResource) getLevelObjects(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		level := project.Archive().Level(int(levelID))
+		hrefBase := "/projects/" + projectID + "/archive/levels/" + fmt.Sprintf("%d", levelID) + "/objects/"
+		var entity model.LevelObjects
+
+		query, queryErr := levelObjectQueryFromRequest(request)
+		if queryErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, queryErr.Error())
+			return
+		}
+		index := resource.objectIndices.get(projectID, int(levelID), level.Objects)
+
+		entity.Table = index.find(query)
+		for i := 0; i < len(entity.Table); i++ {
+			resource.addLevelObjectLinks(projectID, hrefBase, &entity.Table[i])
+		}
+
+		response.WriteEntity(entity)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+func (resource *WorkspaceResource) addLevelObjectLinks(projectID string, hrefBase string, entry *model.LevelObject) {
+	entry.Href = hrefBase + entry.ID
+
+	entry.Links = append(entry.Links, model.Link{
+		Rel:  "static",
+		Href: "/projects/" + projectID + "/objects/" + fmt.Sprintf("%d/%d/%d", entry.Class, entry.Subclass, entry.Type)})
+}
+
+// levelObject returns the object with given ID from the level, including its links.
+func (resource *WorkspaceResource) levelObject(projectID string, level *core.Level, objectID string) (entity model.LevelObject, found bool) {
+	hrefBase := "/projects/" + projectID + "/archive/levels/" + fmt.Sprintf("%d", level.ID()) + "/objects/"
+
+	for _, object := range level.Objects() {
+		if object.ID == objectID {
+			entity = object
+			found = true
+			resource.addLevelObjectLinks(projectID, hrefBase, &entity)
+		}
+	}
+
+	return
+}
+
+// GET /projects/{project-id}/archive/levels/{level-id}/objects/{object-id}
+func (resource *WorkspaceResource) getLevelObject(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		level := project.Archive().Level(int(levelID))
+		entity, found := resource.levelObject(projectID, level, request.PathParameter("object-id"))
+
+		if found {
+			response.WriteEntity(entity)
+		} else {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, "Unknown level object")
+		}
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// PUT /projects/{project-id}/archive/levels/{level-id}/objects/{object-id}
+func (resource *WorkspaceResource) setLevelObject(request *restful.Request, response *restful.Response) {
+	resource.modifyLevelObject(request, response, true)
+}
+
+// PATCH /projects/{project-id}/archive/levels/{level-id}/objects/{object-id}
+func (resource *WorkspaceResource) patchLevelObject(request *restful.Request, response *restful.Response) {
+	resource.modifyLevelObject(request, response, false)
+}
+
+// modifyLevelObject updates a level object from the request. If complete is set, the request
+// must contain the full position of the object.
+func (resource *WorkspaceResource) modifyLevelObject(request *restful.Request, response *restful.Response, complete bool) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		defer resource.locks.lock(levelLockKey(projectID, int(levelID)))()
+		level := project.Archive().Level(int(levelID))
+		objectID := request.PathParameter("object-id")
+		object, found := resource.levelObject(projectID, level, objectID)
+		var properties model.LevelObjectProperties
+
+		if !found {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, "Unknown level object")
+			return
+		}
+		err = request.ReadEntity(&properties)
+		if err == nil {
+			err = validateLevelObjectProperties(&object, &properties, complete)
+		}
+		if err == nil {
+			index, _ := strconv.Atoi(objectID)
+			err = level.SetObject(index, &properties)
+			resource.objectIndices.invalidate(projectID, int(levelID))
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		object, _ = resource.levelObject(projectID, level, objectID)
+		response.WriteEntity(object)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// DELETE /projects/{project-id}/archive/levels/{level-id}/objects/{object-id}
+func (resource *WorkspaceResource) deleteLevelObject(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		defer resource.locks.lock(levelLockKey(projectID, int(levelID)))()
+		level := project.Archive().Level(int(levelID))
+		objectID := request.PathParameter("object-id")
+
+		if _, found := resource.levelObject(projectID, level, objectID); !found {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, "Unknown level object")
+			return
+		}
+		index, _ := strconv.Atoi(objectID)
+		err = level.RemoveObject(index)
+		resource.objectIndices.invalidate(projectID, int(levelID))
+		if err == nil {
+			response.WriteHeader(http.StatusNoContent)
+		} else {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+		}
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// POST /projects/{project-id}/archive/levels/{level-id}/objects/batch
+func (resource *WorkspaceResource) applyLevelObjectBatch(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		level := project.Archive().Level(int(levelID))
+		var batch LevelObjectBatch
+
+		// The batch is validated against, and undone to, the objects as they are now;
+		// no other write may change them in between.
+		defer resource.locks.lock(levelLockKey(projectID, int(levelID)))()
+		err = request.ReadEntity(&batch)
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors := validateLevelObjectBatch(level.Objects(), &batch); len(errors) > 0 {
+			response.WriteHeaderAndEntity(http.StatusBadRequest, OperationErrors{Errors: errors})
+			return
+		}
+
+		result, failedIndex, rollbackErrors, applyErr := applyLevelObjectBatch(level, &batch)
+		resource.objectIndices.invalidate(projectID, int(levelID))
+		if applyErr == nil {
+			response.WriteEntity(result)
+		} else {
+			status := http.StatusConflict
+			if len(rollbackErrors) > 0 {
+				status = http.StatusInternalServerError
+			}
+			response.WriteHeaderAndEntity(status, OperationErrors{
+				Errors:         []OperationError{{Index: failedIndex, Message: applyErr.Error()}},
+				RollbackErrors: rollbackErrors})
+		}
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// POST /projects/{project-id}/archive/levels/{level-id}/objects/copy
+func (resource *WorkspaceResource) copyLevelObjects(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, levelErr := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		var copyRequest LevelObjectCopy
+		var selection []model.LevelObject
+		var mapping map[string]string
+
+		if (levelErr != nil) || !isKnownLevel(project.Archive(), int(levelID)) {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, "Unknown level")
+			return
+		}
+		level := project.Archive().Level(int(levelID))
+		err = request.ReadEntity(&copyRequest)
+		targetProjectID := projectID
+		targetProject := project
+		if (err == nil) && (copyRequest.TargetProject != "") && (copyRequest.TargetProject != projectID) {
+			targetProjectID = copyRequest.TargetProject
+			targetProject, err = resource.ws.Project(targetProjectID)
+		}
+		if (err == nil) && !isKnownLevel(targetProject.Archive(), copyRequest.TargetLevel) {
+			err = fmt.Errorf("Unknown target level %d", copyRequest.TargetLevel)
+		}
+		if err == nil {
+			defer resource.locks.lockAll(levelLockKey(projectID, int(levelID)),
+				levelLockKey(targetProjectID, copyRequest.TargetLevel))()
+			index := resource.objectIndices.get(projectID, int(levelID), level.Objects)
+			selection, err = selectLevelObjects(index, &copyRequest)
+		}
+		if err == nil {
+			targetLevel := targetProject.Archive().Level(copyRequest.TargetLevel)
+			sameLevel := (targetProjectID == projectID) && (copyRequest.TargetLevel == int(levelID))
+			mapping, err = copyLevelObjects(selection, &copyRequest, targetLevel, sameLevel)
+			resource.objectIndices.invalidate(targetProjectID, copyRequest.TargetLevel)
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var entity LevelObjectCopyResult
+		hrefBase := "/projects/" + targetProjectID + "/archive/levels/" + fmt.Sprintf("%d", copyRequest.TargetLevel) + "/objects/"
+		entity.Objects = []CopiedLevelObject{}
+		for _, object := range selection {
+			var entry CopiedLevelObject
+			entry.ID = mapping[object.ID]
+			entry.Href = hrefBase + entry.ID
+			entry.SourceID = object.ID
+			entity.Objects = append(entity.Objects, entry)
+		}
+
+		response.WriteHeader(http.StatusCreated)
+		response.WriteEntity(entity)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// isKnownLevel returns true if the archive contains a level with given ID.
+func isKnownLevel(archive *core.Archive, levelID int) bool {
+	for _, id := range archive.LevelIDs() {
+		if id == levelID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GET /projects/{project-id}/archive/levels/{level-id}/validate/objects
+func (resource *WorkspaceResource) validateLevelObjects(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		level := project.Archive().Level(int(levelID))
+		levelHref := "/projects/" + projectID + "/archive/levels/" + fmt.Sprintf("%d", levelID)
+		var entity LevelObjectIssues
+
+		entity.Href = levelHref + "/validate/objects"
+		entity.List = []LevelObjectIssue{}
+		for _, issue := range validateLevelObjects(level.Objects(), level.TileProperties, project.Archive().LevelIDs()) {
+			var entry LevelObjectIssue
+
+			entry.ID = issue.objectID
+			entry.Href = levelHref + "/objects/" + issue.objectID
+			entry.Kind = issue.kind
+			entry.Message = issue.message
+			entity.List = append(entity.List, entry)
+		}
+
+		response.WriteEntity(entity)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// GET /projects/{project-id}/archive/levels/{level-id}/logic
+func (resource *WorkspaceResource) getLevelLogic(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		entity := resource.levelLogicGraph(project, int(levelID))
+
+		response.WriteEntity(entity)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// GET /projects/{project-id}/archive/levels/{level-id}/logic.dot
+func (resource *WorkspaceResource) getLevelLogicAsDot(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		graph := resource.levelLogicGraph(project, int(levelID))
+
+		response.AddHeader("Content-Type", dotMimeType)
+		writeLogicGraphDot(response, &graph)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+func (resource *WorkspaceResource) levelLogicGraph(project *core.Project, levelID int) (graph LogicGraph) {
+	level := project.Archive().Level(levelID)
+	levelHref := "/projects/" + project.Name() + "/archive/levels/" + fmt.Sprintf("%d", levelID)
+	objectName := func(object *model.LevelObject) string {
+		objID := res.MakeObjectID(res.ObjectClass(object.Class), res.ObjectSubclass(object.Subclass), res.ObjectType(object.Type))
+		properties := project.GameObjects().Properties(objID)
+
+		if properties.LongName[0] != nil {
+			return *properties.LongName[0]
+		}
+		return ""
+	}
+
+	graph = buildLogicGraph(level.Objects(), objectName)
+	graph.Href = levelHref + "/logic"
+	for index := range graph.Nodes {
+		graph.Nodes[index].Href = levelHref + "/objects/" + graph.Nodes[index].ID
+	}
+
+	return
+}
+
+// POST /projects/{project-id}/archive/levels/{level-id}/objects
+func (resource *WorkspaceResource) createLevelObject(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		levelID, _ := strconv.ParseInt(request.PathParameter("level-id"), 10, 16)
+		defer resource.locks.lock(levelLockKey(projectID, int(levelID)))()
+		level := project.Archive().Level(int(levelID))
+
+		entityTemplate := new(model.LevelObjectTemplate)
+		err = request.ReadEntity(entityTemplate)
+		if err == nil {
+			err = validateLevelObjectTemplate(entityTemplate)
+		}
+
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+		var entity int
+
+		entity, err = level.AddObject(entityTemplate)
+		resource.objectIndices.invalidate(projectID, int(levelID))
+		if err == nil {
+			response.WriteHeader(http.StatusCreated)
+			response.WriteEntity(entity)
+		} else {
 			response.AddHeader("Content-Type", "text/plain")
 			response.WriteErrorString(http.StatusBadRequest, err.Error())
+		}
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// GET /projects/{project-id}/objects
+func (resource *WorkspaceResource) getGameObjectClasses(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		var entity GameObjectClasses
+
+		entity.Href = "/projects/" + projectID + "/objects"
+		for class := range objectClasses {
+			entity.List = append(entity.List, resource.objectClassEntity(project, class))
+		}
+
+		response.WriteEntity(entity)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// GET /projects/{project-id}/objects/{class}
+func (resource *WorkspaceResource) getGameObjectClass(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		classID, table, classErr := objectClassFromRequest(request)
+
+		if classErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, classErr.Error())
+		} else if table {
+			resource.writeObjectClassTable(response, project, classID)
+		} else {
+			response.WriteEntity(resource.objectClassEntity(project, classID))
+		}
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// PUT /projects/{project-id}/objects/{class}.csv
+func (resource *WorkspaceResource) setGameObjectClassTable(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		classID, table, classErr := objectClassFromRequest(request)
+
+		if (classErr != nil) || !table {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, "Unknown object class table")
+			return
+		}
+
+		defer resource.locks.lock(objectClassLockKey(projectID, classID))()
+		objIDs, properties := resource.objectClassProperties(project, classID)
+		rows, tableErrors := readObjectTable(request.Request.Body, classID, properties)
+		for index, row := range rows {
+			if err := validateObjectProperties(classID, properties[row.objID], &rows[index].properties); err != nil {
+				tableErrors = append(tableErrors, TableError{Row: row.number, Message: err.Error()})
+			}
+		}
+		// Nothing is written unless every row is valid, so a rejected table leaves the class as it was.
+		if len(tableErrors) > 0 {
+			response.WriteHeaderAndEntity(http.StatusBadRequest, TableErrors{Errors: tableErrors})
+			return
+		}
+
+		for index, row := range rows {
+			project.GameObjects().SetProperties(row.objID, row.properties)
+			properties[row.objID] = &rows[index].properties
+		}
+		response.AddHeader("Content-Type", csvMimeType)
+		writeObjectTable(response, classID, objIDs, properties)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// objectClassFromRequest returns the object class given by the path parameter class, and whether
+// it names the CSV table of the class (with suffix .csv). It fails for parameters that are not
+// numbers and for unknown classes.
+func objectClassFromRequest(request *restful.Request) (class int, table bool, err error) {
+	classParam := request.PathParameter("class")
+	table = strings.HasSuffix(classParam, ".csv")
+	classID, parseErr := strconv.ParseInt(strings.TrimSuffix(classParam, ".csv"), 10, 8)
+
+	class = int(classID)
+	if (parseErr != nil) || !isValidObjectClass(class) {
+		err = fmt.Errorf("Unknown object class")
+	}
+
+	return
+}
+
+func (resource *WorkspaceResource) writeObjectClassTable(response *restful.Response, project *core.Project, class int) {
+	objIDs, properties := resource.objectClassProperties(project, class)
+
+	response.AddHeader("Content-Type", csvMimeType)
+	writeObjectTable(response, class, objIDs, properties)
+}
+
+// objectClassProperties returns the IDs and current properties of all types of given class.
+func (resource *WorkspaceResource) objectClassProperties(project *core.Project,
+	class int) (objIDs []res.ObjectID, properties map[res.ObjectID]*model.GameObjectProperties) {
+	properties = map[res.ObjectID]*model.GameObjectProperties{}
+
+	for subclass := range objectClasses[class].typeCounts {
+		for _, objID := range objectIDs(class, subclass) {
+			entry := project.GameObjects().Properties(objID)
+
+			objIDs = append(objIDs, objID)
+			properties[objID] = &entry
+		}
+	}
+
+	return
+}
+
+// GET /projects/{project-id}/objects/{class}/{subclass}
+func (resource *WorkspaceResource) getGameObjectSubclass(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		classID, classErr := strconv.ParseInt(request.PathParameter("class"), 10, 8)
+		subclassID, subclassErr := strconv.ParseInt(request.PathParameter("subclass"), 10, 8)
+
+		if (classErr == nil) && (subclassErr == nil) && isValidObjectSubclass(int(classID), int(subclassID)) {
+			response.WriteEntity(resource.objectSubclassEntity(project, int(classID), int(subclassID)))
+		} else {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, "Unknown object subclass")
+		}
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+func (resource *WorkspaceResource) objectClassEntity(project *core.Project, class int) (entity GameObjectClass) {
+	entity.ID = fmt.Sprintf("%d", class)
+	entity.Href = "/projects/" + project.Name() + "/objects/" + entity.ID
+	entity.Name = objectClasses[class].name
+	for subclass := range objectClasses[class].typeCounts {
+		entity.Subclasses = append(entity.Subclasses, resource.objectSubclassEntity(project, class, subclass))
+	}
+
+	return
+}
+
+func (resource *WorkspaceResource) objectSubclassEntity(project *core.Project, class, subclass int) (entity GameObjectSubclass) {
+	entity.ID = fmt.Sprintf("%d/%d", class, subclass)
+	entity.Href = "/projects/" + project.Name() + "/objects/" + entity.ID
+	for _, objID := range objectIDs(class, subclass) {
+		var entry GameObjectType
+		properties := project.GameObjects().Properties(objID)
+
+		entry.ID = fmt.Sprintf("%d/%d/%d", objID.Class, objID.Subclass, objID.Type)
+		entry.Href = "/projects/" + project.Name() + "/objects/" + entry.ID
+		if properties.LongName[0] != nil {
+			entry.Name = *properties.LongName[0]
+		}
+		entity.Types = append(entity.Types, entry)
+	}
+
+	return
+}
+
+// GET /projects/{project-id}/objects/{class}/{subclass}/{type}
+func (resource *WorkspaceResource) getGameObject(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		entity := resource.objectEntity(project, objID)
+
+		response.WriteEntity(entity)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// PUT /projects/{project-id}/objects/{class}/{subclass}/{type}
+func (resource *WorkspaceResource) setGameObject(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		var properties model.GameObjectProperties
+
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
 			return
 		}
-		var entity int
-
-		entity, err = level.AddObject(entityTemplate)
+		defer resource.locks.lock(objectClassLockKey(projectID, int(objID.Class)))()
+		err = request.ReadEntity(&properties)
 		if err == nil {
-			response.WriteHeader(http.StatusCreated)
-			response.WriteEntity(entity)
-		} else {
+			current := project.GameObjects().Properties(objID)
+			err = validateObjectProperties(int(objID.Class), &current, &properties)
+		}
+		if err != nil {
 			response.AddHeader("Content-Type", "text/plain")
 			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
 		}
+
+		project.GameObjects().SetProperties(objID, properties)
+		entity := resource.objectEntity(project, objID)
+
+		response.WriteEntity(entity)
 	} else {
 		response.AddHeader("Content-Type", "text/plain")
 		response.WriteErrorString(http.StatusBadRequest, err.Error())
@@ -758,19 +2428,59 @@ func (resource *WorkspaceResource) createLevelObject(request *restful.Request, r
 	}
 }
 
-// GET /projects/{project-id}/objects/{class}/{subclass}/{type}
-func (resource *WorkspaceResource) getGameObject(request *restful.Request, response *restful.Response) {
+// GET /projects/{project-id}/objects/{class}/{subclass}/{type}/fields
+func (resource *WorkspaceResource) getGameObjectFields(request *restful.Request, response *restful.Response) {
 	projectID := request.PathParameter("project-id")
 	project, err := resource.ws.Project(projectID)
 
 	if err == nil {
-		classID, _ := strconv.ParseInt(request.PathParameter("class"), 10, 8)
-		subclassID, _ := strconv.ParseInt(request.PathParameter("subclass"), 10, 8)
-		typeID, _ := strconv.ParseInt(request.PathParameter("type"), 10, 8)
-		objID := res.MakeObjectID(res.ObjectClass(classID), res.ObjectSubclass(subclassID), res.ObjectType(typeID))
-		entity := resource.objectEntity(project, objID)
+		objID, idErr := knownObjectIDFromRequest(request)
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		properties := project.GameObjects().Properties(objID)
 
-		response.WriteEntity(entity)
+		response.WriteEntity(objectFieldValues(int(objID.Class), &properties.Data))
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// PUT /projects/{project-id}/objects/{class}/{subclass}/{type}/fields
+func (resource *WorkspaceResource) setGameObjectFields(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		var properties model.GameObjectProperties
+		var fields GameObjectFields
+
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		defer resource.locks.lock(objectClassLockKey(projectID, int(objID.Class)))()
+		err = request.ReadEntity(&fields)
+		if err == nil {
+			properties = project.GameObjects().Properties(objID)
+			err = setObjectFieldValues(int(objID.Class), &properties, fields)
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		project.GameObjects().SetProperties(objID, properties)
+		properties = project.GameObjects().Properties(objID)
+
+		response.WriteEntity(objectFieldValues(int(objID.Class), &properties.Data))
 	} else {
 		response.AddHeader("Content-Type", "text/plain")
 		response.WriteErrorString(http.StatusBadRequest, err.Error())
@@ -786,27 +2496,288 @@ func (resource *WorkspaceResource) objectEntity(project *core.Project, objID res
 	return
 }
 
-// GET /projects/{project-id}/objects/{class}/{subclass}/{type}/icon/raw
-func (resource *WorkspaceResource) getObjectIconAsRaw(request *restful.Request, response *restful.Response) {
+// GET /projects/{project-id}/objects/{class}/{subclass}/{type}/icon
+func (resource *WorkspaceResource) getObjectIcon(request *restful.Request, response *restful.Response) {
 	projectID := request.PathParameter("project-id")
 	project, err := resource.ws.Project(projectID)
 
 	if err == nil {
-		classID, _ := strconv.ParseInt(request.PathParameter("class"), 10, 8)
-		subclassID, _ := strconv.ParseInt(request.PathParameter("subclass"), 10, 8)
-		typeID, _ := strconv.ParseInt(request.PathParameter("type"), 10, 8)
-		objID := res.MakeObjectID(res.ObjectClass(classID), res.ObjectSubclass(subclassID), res.ObjectType(typeID))
+		objID, idErr := knownObjectIDFromRequest(request)
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		href := "/projects/" + projectID + "/objects/" + fmt.Sprintf("%d/%d/%d", objID.Class, objID.Subclass, objID.Type) + "/icon"
 		bmp := project.GameObjects().Icon(objID)
-		var entity model.RawBitmap
 
-		entity.Width = int(bmp.ImageWidth())
-		entity.Height = int(bmp.ImageHeight())
-		var pixel []byte
+		response.WriteEntity(imageEntity(href, bmp, "png", "raw"))
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// PUT /projects/{project-id}/objects/{class}/{subclass}/{type}/icon
+func (resource *WorkspaceResource) setObjectIcon(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		href := "/projects/" + projectID + "/objects/" + fmt.Sprintf("%d/%d/%d", objID.Class, objID.Subclass, objID.Type) + "/icon"
+		var properties model.ImageProperties
 
-		for row := 0; row < entity.Height; row++ {
-			pixel = append(pixel, bmp.Row(row)...)
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		defer resource.locks.lock(objectClassLockKey(projectID, int(objID.Class)))()
+		err = request.ReadEntity(&properties)
+		if err == nil {
+			err = validateImageProperties(properties, project.GameObjects().Icon(objID))
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		project.GameObjects().SetIconProperties(objID, properties)
+		bmp := project.GameObjects().Icon(objID)
+
+		response.WriteEntity(imageEntity(href, bmp, "png", "raw"))
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// GET /projects/{project-id}/objects/{class}/{subclass}/{type}/icon/png
+func (resource *WorkspaceResource) getObjectIconAsPng(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		bmp := project.GameObjects().Icon(objID)
+
+		resource.writePng(request, response, project, bmp)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// PUT /projects/{project-id}/objects/{class}/{subclass}/{type}/icon/png
+func (resource *WorkspaceResource) setObjectIconFromPng(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		href := "/projects/" + projectID + "/objects/" + fmt.Sprintf("%d/%d/%d", objID.Class, objID.Subclass, objID.Type) + "/icon"
+		var palette color.Palette
+		var img goimage.Image
+
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		defer resource.locks.lock(objectClassLockKey(projectID, int(objID.Class)))()
+		img, err = png.Decode(request.Request.Body)
+		if err == nil {
+			err = validateIconSize(img)
+		}
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, err.Error())
+			return
+		}
+		palette, err = project.Palettes().GamePalette()
+		if err != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		project.GameObjects().SetIcon(objID, quantizedImage(img, palette))
+		bmp := project.GameObjects().Icon(objID)
+
+		response.WriteEntity(imageEntity(href, bmp, "png", "raw"))
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// GET /projects/{project-id}/objects/{class}/{subclass}/{type}/bitmaps
+func (resource *WorkspaceResource) getObjectBitmaps(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		gameObjects := project.GameObjects()
+		var entity ObjectBitmaps
+
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		entity.Href = "/projects/" + projectID + "/objects/" + fmt.Sprintf("%d/%d/%d", objID.Class, objID.Subclass, objID.Type) + "/bitmaps"
+		entity.List = []ObjectBitmap{}
+		for frame := 0; frame < gameObjects.BitmapCount(objID); frame++ {
+			href := entity.Href + "/" + fmt.Sprintf("%d", frame)
+			entity.List = append(entity.List, ObjectBitmap{
+				Image: imageEntity(href, gameObjects.Bitmap(objID, frame), "png", "raw"),
+				Role:  objectBitmapRole(objID, frame)})
+		}
+
+		response.WriteEntity(entity)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// GET /projects/{project-id}/objects/{class}/{subclass}/{type}/bitmaps/{frame}
+func (resource *WorkspaceResource) getObjectBitmap(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		frame, frameValid := resource.objectBitmapFrame(request, project, objID)
+
+		if frameValid {
+			href := "/projects/" + projectID + "/objects/" + fmt.Sprintf("%d/%d/%d", objID.Class, objID.Subclass, objID.Type) +
+				"/bitmaps/" + fmt.Sprintf("%d", frame)
+			response.WriteEntity(imageEntity(href, project.GameObjects().Bitmap(objID, frame), "png", "raw"))
+		} else {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, "Unknown bitmap")
+		}
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// GET /projects/{project-id}/objects/{class}/{subclass}/{type}/bitmaps/{frame}/raw
+func (resource *WorkspaceResource) getObjectBitmapAsRaw(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		frame, frameValid := resource.objectBitmapFrame(request, project, objID)
+
+		if frameValid {
+			writeRawBitmap(request, response, project.GameObjects().Bitmap(objID, frame))
+		} else {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, "Unknown bitmap")
+		}
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// GET /projects/{project-id}/objects/{class}/{subclass}/{type}/bitmaps/{frame}/png
+func (resource *WorkspaceResource) getObjectBitmapAsPng(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		frame, frameValid := resource.objectBitmapFrame(request, project, objID)
+
+		if frameValid {
+			resource.writePng(request, response, project, project.GameObjects().Bitmap(objID, frame))
+		} else {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, "Unknown bitmap")
+		}
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// objectBitmapFrame returns the bitmap index given by the path parameter frame, and whether
+// the object has such a bitmap.
+func (resource *WorkspaceResource) objectBitmapFrame(request *restful.Request, project *core.Project, objID res.ObjectID) (int, bool) {
+	frame, err := strconv.ParseInt(request.PathParameter("frame"), 10, 16)
+
+	return int(frame), (err == nil) && (frame >= 0) && (int(frame) < project.GameObjects().BitmapCount(objID))
+}
+
+// GET /projects/{project-id}/objects/{class}/{subclass}/{type}/placements
+func (resource *WorkspaceResource) getObjectPlacements(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		archive := project.Archive()
+		var entity ObjectPlacements
+
+		entity.Href = "/projects/" + projectID + "/objects/" + fmt.Sprintf("%d/%d/%d", objID.Class, objID.Subclass, objID.Type) + "/placements"
+		entity.List = []ObjectPlacement{}
+		for _, levelID := range archive.LevelIDs() {
+			hrefBase := "/projects/" + projectID + "/archive/levels/" + fmt.Sprintf("%d", levelID) + "/objects/"
+
+			for _, object := range archive.Level(levelID).Objects() {
+				if (object.Class == int(objID.Class)) && (object.Subclass == int(objID.Subclass)) && (object.Type == int(objID.Type)) {
+					var entry ObjectPlacement
+
+					entry.ID = object.ID
+					entry.Href = hrefBase + object.ID
+					entry.LevelID = levelID
+					entry.TileX = object.BaseProperties.TileX
+					entry.TileY = object.BaseProperties.TileY
+					entity.List = append(entity.List, entry)
+				}
+			}
 		}
-		entity.Pixels = base64.StdEncoding.EncodeToString(pixel)
 
 		response.WriteEntity(entity)
 	} else {
@@ -815,3 +2786,44 @@ func (resource *WorkspaceResource) getObjectIconAsRaw(request *restful.Request,
 		return
 	}
 }
+
+// knownObjectIDFromRequest returns the object ID given by the path parameters class, subclass and type.
+// It fails for parameters that are not numbers and for unknown objects.
+func knownObjectIDFromRequest(request *restful.Request) (objID res.ObjectID, err error) {
+	var ids [3]int64
+
+	for index, name := range []string{"class", "subclass", "type"} {
+		ids[index], err = strconv.ParseInt(request.PathParameter(name), 10, 8)
+		if err != nil {
+			return objID, fmt.Errorf("Unknown object type")
+		}
+	}
+	objID = res.MakeObjectID(res.ObjectClass(ids[0]), res.ObjectSubclass(ids[1]), res.ObjectType(ids[2]))
+	if !isValidObjectID(objID) {
+		err = fmt.Errorf("Unknown object type")
+	}
+
+	return
+}
+
+// GET /projects/{project-id}/objects/{class}/{subclass}/{type}/icon/raw
+func (resource *WorkspaceResource) getObjectIconAsRaw(request *restful.Request, response *restful.Response) {
+	projectID := request.PathParameter("project-id")
+	project, err := resource.ws.Project(projectID)
+
+	if err == nil {
+		objID, idErr := knownObjectIDFromRequest(request)
+		if idErr != nil {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, idErr.Error())
+			return
+		}
+		bmp := project.GameObjects().Icon(objID)
+
+		writeRawBitmap(request, response, bmp)
+	} else {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+}